@@ -0,0 +1,99 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+)
+
+// Adaptive configures adaptive sampling. Rather than casting a fixed
+// number of rays through every pixel, a pixel keeps receiving further
+// passes of the Sampler until the standard error of its mean color falls
+// below Threshold, or it has been given MaxPasses passes.
+// Every pixel is given at least MinPasses passes, which must be at least
+// two for the variance to mean anything.
+// A RegularGrid or RotatedGrid Sampler is replaced by a Jittered one
+// with the same N, so that every pass casts different rays.
+type Adaptive struct {
+	MinPasses int
+	MaxPasses int
+	Threshold float64
+}
+
+// passes returns the clamped minimum and maximum number of passes
+func (a *Adaptive) passes() (int, int) {
+	if a == nil {
+		return 1, 1
+	}
+	min, max := a.MinPasses, a.MaxPasses
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
+
+// converged reports wether the pixel has settled on its color
+func (a *Adaptive) converged(s pixelStats) bool {
+	if a == nil || s.n < 2 {
+		return false
+	}
+	return s.standardError() < a.Threshold
+}
+
+// pixelStats keeps a running mean and variance of the samples cast
+// through a single pixel, using Welford's online algorithm.
+type pixelStats struct {
+	n    int
+	mean canvas.Color
+	m2   canvas.Color
+}
+
+func (s *pixelStats) add(c canvas.Color) {
+	s.n++
+	delta := c.Subtract(s.mean)
+	s.mean = s.mean.Add(delta.Scale(1 / float64(s.n)))
+	s.m2 = s.m2.Add(delta.Multiply(c.Subtract(s.mean)))
+}
+
+// standardError returns the largest standard error of the
+// mean across the three color channels
+func (s pixelStats) standardError() float64 {
+	if s.n < 2 {
+		return math.Inf(1)
+	}
+	worst := math.Max(s.m2.R(), math.Max(s.m2.G(), s.m2.B()))
+	variance := worst / float64(s.n-1)
+	return math.Sqrt(variance / float64(s.n))
+}
+
+// heatMap converts per-pixel sample counts into a grayscale canvas,
+// where white is the largest count in the image.
+func heatMap(counts [][]int) canvas.Canvas {
+	h := len(counts)
+	w := 0
+	if h > 0 {
+		w = len(counts[0])
+	}
+	img := canvas.NewCanvas(w, h)
+	max := 0
+	for y := range counts {
+		for x := range counts[y] {
+			if counts[y][x] > max {
+				max = counts[y][x]
+			}
+		}
+	}
+	if max == 0 {
+		return img
+	}
+	for y := range counts {
+		for x := range counts[y] {
+			v := float64(counts[y][x]) / float64(max)
+			img.WritePixel(x, y, canvas.NewColor(v, v, v))
+		}
+	}
+	return img
+}
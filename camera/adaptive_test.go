@@ -0,0 +1,80 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptivePasses(t *testing.T) {
+	is := assert.New(t)
+
+	var none *Adaptive
+	min, max := none.passes()
+	is.Equal(1, min)
+	is.Equal(1, max)
+
+	min, max = (&Adaptive{MinPasses: 0, MaxPasses: 8}).passes()
+	is.Equal(1, min)
+	is.Equal(8, max)
+
+	min, max = (&Adaptive{MinPasses: 4, MaxPasses: 2}).passes()
+	is.Equal(4, min)
+	is.Equal(4, max)
+}
+
+func TestPixelStats(t *testing.T) {
+	is := assert.New(t)
+
+	var s pixelStats
+	is.True(math.IsInf(s.standardError(), 1))
+
+	s.add(canvas.NewColor(1, 0, 0))
+	s.add(canvas.NewColor(0, 0, 0))
+	s.add(canvas.NewColor(1, 0, 0))
+	s.add(canvas.NewColor(0, 0, 0))
+
+	is.Equal(4, s.n)
+	is.True(s.mean.Equal(canvas.NewColor(0.5, 0, 0)))
+	// sample variance of the red channel is 1/3
+	is.True(float.Equal(math.Sqrt(1.0/3/4), s.standardError()))
+}
+
+func TestAdaptiveConverged(t *testing.T) {
+	is := assert.New(t)
+
+	a := &Adaptive{MinPasses: 2, MaxPasses: 4, Threshold: 0.01}
+
+	var flat pixelStats
+	flat.add(canvas.NewColor(0.2, 0.2, 0.2))
+	is.False(a.converged(flat))
+	flat.add(canvas.NewColor(0.2, 0.2, 0.2))
+	is.True(a.converged(flat))
+
+	var noisy pixelStats
+	noisy.add(canvas.NewColor(0, 0, 0))
+	noisy.add(canvas.NewColor(1, 1, 1))
+	is.False(a.converged(noisy))
+
+	var none *Adaptive
+	is.False(none.converged(flat))
+}
+
+func TestHeatMap(t *testing.T) {
+	is := assert.New(t)
+
+	img := heatMap([][]int{
+		{4, 8},
+		{0, 2},
+	})
+	is.Equal(2, img.Width)
+	is.Equal(2, img.Height)
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	is.True(img.PixelAt(1, 0).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(0, 1).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(img.PixelAt(1, 1).Equal(canvas.NewColor(0.25, 0.25, 0.25)))
+}
@@ -0,0 +1,154 @@
+package camera
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/muzfuz/raytrace/logging"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Camera maps the three-dimensional scene onto a two-dimensional canvas.
+// Projection shapes the rays cast through the canvas, and the
+// transform describes how the world is oriented relative to the camera.
+// New gives the camera a Perspective projection, and Projection must
+// not be nil.
+// Sampler and Filter control anti-aliasing. When Sampler is nil a single
+// ray is cast through the center of each pixel, and when Filter is nil
+// each pixel only averages its own samples. Seed seeds the random
+// numbers used by jittered samplers, so renders are repeatable.
+// Adaptive is optional, when it is nil every pixel gets one pass of the Sampler.
+// Adaptive passes are always jittered, see Adaptive.
+// Logger is optional, when it is nil the camera logs nothing.
+// Aperture is the diameter of the lens, in world space units. When it is
+// 0 the camera is a pinhole and everything is in focus. Otherwise rays
+// start from random points on the lens and only objects FocalDistance
+// in front of the camera are sharp. Rays that never reach that far in
+// front, such as the sides of a wide fisheye, are always sharp.
+// A FocalDistance of 0 or less is treated as 1, the distance New sets.
+// ShutterOpen and ShutterClose are the times the shutter opens and
+// closes. Rays are cast at random times between them, so that moving
+// objects are blurred. When they are equal every ray is cast at ShutterOpen.
+type Camera struct {
+	HSize         int
+	VSize         int
+	Projection    Projection
+	Transform     matrix.Matrix
+	Sampler       Sampler
+	Filter        Filter
+	Seed          int64
+	Adaptive      *Adaptive
+	Logger        logging.Logger
+	Aperture      float64
+	FocalDistance float64
+	ShutterOpen   float64
+	ShutterClose  float64
+}
+
+// New constructs a perspective Camera with a horizontal size,
+// vertical size and field of view (in radians).
+func New(hsize, vsize int, fieldOfView float64) Camera {
+	return Camera{
+		HSize:         hsize,
+		VSize:         vsize,
+		Projection:    Perspective{FieldOfView: fieldOfView},
+		Transform:     matrix.Identity(),
+		FocalDistance: 1,
+	}
+}
+
+// RayForPixel returns a ray that starts at the camera
+// and passes through the center of the given pixel on the canvas.
+func (c Camera) RayForPixel(px, py int) (ray.Ray, error) {
+	inv, err := c.inverse()
+	if err != nil {
+		return ray.Ray{}, err
+	}
+	r, ok := c.rayAt(inv, float64(px)+0.5, float64(py)+0.5, 0, 0)
+	if !ok {
+		return ray.Ray{}, errors.New("pixel is outside the camera's projection")
+	}
+	r.Time = c.ShutterOpen
+	return r, nil
+}
+
+// inverse returns the inverse of the camera's transform as a Mat4.
+// It also fails for a camera without a Projection, as both are needed
+// before any ray can be cast.
+func (c Camera) inverse() (matrix.Mat4, error) {
+	if c.Projection == nil {
+		return matrix.Mat4{}, errors.New("camera has no projection")
+	}
+	m, err := c.Transform.ToMat4()
+	if err != nil {
+		return m, err
+	}
+	return m.Inverse()
+}
+
+// rayAt returns a ray through the continuous canvas position x, y,
+// where the center of pixel (0, 0) is at (0.5, 0.5), starting from
+// lensX, lensY on the lens. It returns false when the position
+// is outside the camera's Projection.
+// It takes an already inverted transform, so that rendering only inverts once.
+func (c Camera) rayAt(inv matrix.Mat4, x, y, lensX, lensY float64) (ray.Ray, bool) {
+	r, ok := c.Projection.Ray(x, y, c.HSize, c.VSize)
+	if !ok {
+		return r, false
+	}
+
+	// every ray through the position meets on the focal plane,
+	// so moving its origin across the lens only blurs what is off it
+	if (lensX != 0 || lensY != 0) && r.Direction.Z < 0 {
+		f := c.FocalDistance
+		if f <= 0 {
+			f = 1
+		}
+		focus := r.Position((-f - r.Origin.Z) / r.Direction.Z)
+		r.Origin = r.Origin.Add(tuple.Vec(lensX, lensY, 0))
+		r.Direction = focus.Subtract(r.Origin)
+	}
+
+	r = r.Transform(inv)
+	r.Direction = r.Direction.Normalize()
+	return r, true
+}
+
+// lens picks a random point on the lens, in camera space.
+// A pinhole camera always uses the center of the lens.
+func (c Camera) lens(rnd *rand.Rand) (float64, float64) {
+	if c.Aperture <= 0 {
+		return 0, 0
+	}
+	x, y := concentricDisc(rnd.Float64(), rnd.Float64())
+	return x * c.Aperture / 2, y * c.Aperture / 2
+}
+
+// shutter picks a random time while the shutter is open.
+// An instant shutter always uses the time it opens.
+func (c Camera) shutter(rnd *rand.Rand) float64 {
+	if c.ShutterClose <= c.ShutterOpen {
+		return c.ShutterOpen
+	}
+	return c.ShutterOpen + rnd.Float64()*(c.ShutterClose-c.ShutterOpen)
+}
+
+// concentricDisc maps two uniform random numbers onto the unit disc,
+// keeping stratified samples evenly spread (Shirley and Chiu).
+func concentricDisc(u1, u2 float64) (float64, float64) {
+	a, b := 2*u1-1, 2*u2-1
+	if a == 0 && b == 0 {
+		return 0, 0
+	}
+	var r, theta float64
+	if math.Abs(a) > math.Abs(b) {
+		r, theta = a, math.Pi/4*(b/a)
+	} else {
+		r, theta = b, math.Pi/2-math.Pi/4*(a/b)
+	}
+	sin, cos := math.Sincos(theta)
+	return r * cos, r * sin
+}
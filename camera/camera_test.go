@@ -0,0 +1,176 @@
+package camera
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCamera(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(160, 120, math.Pi/2)
+
+	is.Equal(160, c.HSize)
+	is.Equal(120, c.VSize)
+	is.Equal(Perspective{FieldOfView: math.Pi / 2}, c.Projection)
+	is.Equal(matrix.Identity(), c.Transform)
+	is.Equal(0.0, c.Aperture)
+	is.Equal(1.0, c.FocalDistance)
+}
+
+func TestPixelSize(t *testing.T) {
+	is := assert.New(t)
+
+	p := Perspective{FieldOfView: math.Pi / 2}
+
+	// horizontal canvas
+	is.True(float.Equal(0.01, p.PixelSize(200, 125)))
+
+	// vertical canvas
+	is.True(float.Equal(0.01, p.PixelSize(125, 200)))
+}
+
+func TestRayThroughCenterOfCanvas(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	r, err := c.RayForPixel(100, 50)
+	is.NoError(err)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+}
+
+func TestRayThroughCornerOfCanvas(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	r, err := c.RayForPixel(0, 0)
+	is.NoError(err)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0.66519, 0.33259, -0.66851)))
+}
+
+func TestRayWhenCameraIsTransformed(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	c.Transform = matrix.RotationY(math.Pi / 4).Multiply(matrix.Translation(0, -2, 5))
+	r, err := c.RayForPixel(100, 50)
+	is.NoError(err)
+	is.True(r.Origin.Equal(tuple.Pt(0, 2, -5)))
+	is.True(r.Direction.Equal(tuple.Vec(math.Sqrt(2)/2, 0, -math.Sqrt(2)/2)))
+}
+
+func TestRayWhenTransformIsNotInvertible(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(10, 10, math.Pi/2)
+	c.Transform = matrix.Scaling(0, 0, 0)
+	_, err := c.RayForPixel(5, 5)
+	is.Error(err)
+}
+
+func TestRaysThroughLensMeetAtFocalPlane(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	c.Aperture = 0.5
+	c.FocalDistance = 5
+	c.Transform = matrix.Identity().Translate(0, -2, 5)
+	inv, err := c.inverse()
+	is.NoError(err)
+
+	// a pinhole ray picks out the point that should be in focus
+	r, err := c.RayForPixel(30, 80)
+	is.NoError(err)
+	is.True(r.Origin.Equal(tuple.Pt(0, 2, -5)))
+	focus := r.Position(5 / -r.Direction.Z)
+
+	for _, lens := range [][2]float64{{0.25, 0}, {-0.1, 0.2}, {0, -0.25}} {
+		r, ok := c.rayAt(inv, 30.5, 80.5, lens[0], lens[1])
+		is.True(ok)
+		is.True(r.Origin.Equal(tuple.Pt(lens[0], 2+lens[1], -5)))
+		is.True(float.Equal(1, r.Direction.Magnitude()))
+		is.True(r.Position(5 / -r.Direction.Z).Equal(focus))
+	}
+}
+
+func TestUnsetFocalDistance(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	expected, err := c.RayForPixel(30, 80)
+	is.NoError(err)
+
+	for _, f := range []float64{0, -2} {
+		c.FocalDistance = f
+		r, err := c.RayForPixel(30, 80)
+		is.NoError(err)
+		is.True(r.Direction.Equal(expected.Direction))
+
+		c.Aperture = 0.5
+		inv, err := c.inverse()
+		is.NoError(err)
+		r, _ = c.rayAt(inv, 30.5, 80.5, 0.25, 0)
+		is.True(float.Equal(1, r.Direction.Magnitude()))
+		c.Aperture = 0
+	}
+}
+
+func TestConcentricDisc(t *testing.T) {
+	is := assert.New(t)
+
+	x, y := concentricDisc(0.5, 0.5)
+	is.Equal(0.0, x)
+	is.Equal(0.0, y)
+
+	x, y = concentricDisc(1, 0.5)
+	is.True(float.Equal(1, x))
+	is.True(float.Equal(0, y))
+
+	x, y = concentricDisc(0.5, 0)
+	is.True(float.Equal(0, x))
+	is.True(float.Equal(-1, y))
+
+	// a grid over the square stays inside the disc
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 10; j++ {
+			x, y := concentricDisc(float64(i)/10, float64(j)/10)
+			is.True(math.Hypot(x, y) <= 1+1e-9)
+		}
+	}
+}
+
+func TestPinholeLens(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(10, 10, math.Pi/2)
+	x, y := c.lens(nil)
+	is.Equal(0.0, x)
+	is.Equal(0.0, y)
+}
+
+func TestShutter(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(10, 10, math.Pi/2)
+	c.ShutterOpen = 0.5
+	is.Equal(0.5, c.shutter(nil))
+	r, err := c.RayForPixel(5, 5)
+	is.NoError(err)
+	is.Equal(0.5, r.Time)
+
+	c.ShutterClose = 1.5
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		time := c.shutter(rnd)
+		is.True(time >= 0.5 && time < 1.5)
+	}
+}
@@ -0,0 +1,74 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+)
+
+// film accumulates filtered samples before they are written to a canvas.
+// A sample can contribute to every pixel within the filter's radius,
+// not just the pixel it was cast through.
+type film struct {
+	width   int
+	height  int
+	filter  Filter
+	colors  [][]canvas.Color
+	weights [][]float64
+}
+
+func newFilm(w, h int, filter Filter) film {
+	colors := make([][]canvas.Color, h)
+	weights := make([][]float64, h)
+	for y := range colors {
+		colors[y] = make([]canvas.Color, w)
+		weights[y] = make([]float64, w)
+	}
+	return film{
+		width:   w,
+		height:  h,
+		filter:  filter,
+		colors:  colors,
+		weights: weights,
+	}
+}
+
+// addSample splats a color at the continuous canvas position x, y
+func (f film) addSample(x, y float64, color canvas.Color) {
+	r := f.filter.Radius()
+	minX := int(math.Max(0, math.Ceil(x-0.5-r)))
+	maxX := int(math.Min(float64(f.width-1), math.Floor(x-0.5+r)))
+	minY := int(math.Max(0, math.Ceil(y-0.5-r)))
+	maxY := int(math.Min(float64(f.height-1), math.Floor(y-0.5+r)))
+	for py := minY; py <= maxY; py++ {
+		for px := minX; px <= maxX; px++ {
+			w := f.filter.Weight(x-(float64(px)+0.5), y-(float64(py)+0.5))
+			if w == 0 {
+				continue
+			}
+			f.colors[py][px] = f.colors[py][px].Add(color.Scale(w))
+			f.weights[py][px] += w
+		}
+	}
+}
+
+// minWeight is the smallest total weight a pixel needs to be developed.
+// Filters with negative lobes, like Mitchell's, can leave a pixel with
+// few samples near its edge with a total weight close to or below zero,
+// and dividing by it would blow the color up or flip its sign.
+const minWeight = 0.001
+
+// develop writes the weighted average of every pixel's samples to img.
+// Pixels that no sample, or too little weight, contributed to are left
+// untouched. Negative lobes can still ring below zero, which is clamped.
+func (f film) develop(img canvas.Canvas) {
+	for y := range f.colors {
+		for x := range f.colors[y] {
+			if f.weights[y][x] <= minWeight {
+				continue
+			}
+			c := f.colors[y][x].Scale(1 / f.weights[y][x])
+			img.WritePixel(x, y, canvas.NewColor(math.Max(0, c.R()), math.Max(0, c.G()), math.Max(0, c.B())))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilmOnlyAveragesOwnSamples(t *testing.T) {
+	is := assert.New(t)
+
+	f := newFilm(3, 1, NewBoxFilter(0.5))
+	f.addSample(1.25, 0.5, canvas.NewColor(1, 1, 1))
+	f.addSample(1.75, 0.5, canvas.NewColor(0, 0, 0))
+
+	img := canvas.NewCanvas(3, 1)
+	f.develop(img)
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(img.PixelAt(1, 0).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	is.True(img.PixelAt(2, 0).Equal(canvas.NewColor(0, 0, 0)))
+}
+
+func TestFilmSpreadsSamplesToNeighbours(t *testing.T) {
+	is := assert.New(t)
+
+	f := newFilm(3, 1, NewTentFilter(1.5))
+	f.addSample(0.5, 0.5, canvas.NewColor(1, 0, 0))
+	f.addSample(2.5, 0.5, canvas.NewColor(0, 0, 1))
+
+	img := canvas.NewCanvas(3, 1)
+	f.develop(img)
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(1, 0, 0)))
+	is.True(img.PixelAt(1, 0).Equal(canvas.NewColor(0.5, 0, 0.5)))
+	is.True(img.PixelAt(2, 0).Equal(canvas.NewColor(0, 0, 1)))
+}
+
+func TestFilmSparseNegativeLobes(t *testing.T) {
+	is := assert.New(t)
+
+	// pixel 0 only sees these samples through the edge of the filter,
+	// where the negative lobe outweighs the positive one
+	f := newFilm(3, 1, NewMitchellFilter(2, 1.0/3, 1.0/3))
+	f.addSample(1.5, 0.5, canvas.NewColor(1, 1, 1))
+	f.addSample(2.0, 0.5, canvas.NewColor(0, 0, 0))
+	f.addSample(2.1, 0.5, canvas.NewColor(0, 0, 0))
+	is.True(f.weights[0][0] < 0)
+
+	img := canvas.NewCanvas(3, 1)
+	img.WriteAllPixels(canvas.NewColor(0.5, 0.5, 0.5))
+	f.develop(img)
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+
+	// a bright sample in a negative lobe cannot push a pixel below zero
+	f = newFilm(3, 1, NewMitchellFilter(2, 1.0/3, 1.0/3))
+	f.addSample(0.5, 0.5, canvas.NewColor(0, 0, 0))
+	f.addSample(2.0, 0.5, canvas.NewColor(10, 10, 10))
+	f.develop(img)
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(0, 0, 0)))
+	for x := 0; x < 3; x++ {
+		c := img.PixelAt(x, 0)
+		is.True(c.R() >= 0 && !math.IsNaN(c.R()) && !math.IsInf(c.R(), 0))
+	}
+}
@@ -0,0 +1,131 @@
+package camera
+
+import "math"
+
+// Filter is a reconstruction filter. It weights the contribution of
+// a sample to a pixel by the sample's distance from the pixel's center.
+// Samples further away than the Radius on either axis are ignored.
+type Filter interface {
+	Radius() float64
+	Weight(dx, dy float64) float64
+}
+
+// BoxFilter weights every sample within its radius equally
+type BoxFilter struct {
+	radius float64
+}
+
+// NewBoxFilter constructs a BoxFilter.
+// A radius of 0.5 only takes samples from within the pixel itself.
+func NewBoxFilter(radius float64) BoxFilter {
+	return BoxFilter{radius: radius}
+}
+
+// Radius returns the filter radius
+func (f BoxFilter) Radius() float64 {
+	return f.radius
+}
+
+// Weight returns 1 for samples within the radius
+func (f BoxFilter) Weight(dx, dy float64) float64 {
+	if math.Abs(dx) > f.radius || math.Abs(dy) > f.radius {
+		return 0
+	}
+	return 1
+}
+
+// TentFilter weights samples linearly less the further
+// they are from the pixel's center
+type TentFilter struct {
+	radius float64
+}
+
+// NewTentFilter constructs a TentFilter
+func NewTentFilter(radius float64) TentFilter {
+	return TentFilter{radius: radius}
+}
+
+// Radius returns the filter radius
+func (f TentFilter) Radius() float64 {
+	return f.radius
+}
+
+// Weight falls off linearly on both axes
+func (f TentFilter) Weight(dx, dy float64) float64 {
+	return math.Max(0, f.radius-math.Abs(dx)) * math.Max(0, f.radius-math.Abs(dy))
+}
+
+// GaussianFilter weights samples using a gaussian bump,
+// shifted down so that it reaches zero at the radius.
+// A larger alpha makes for a narrower, sharper filter.
+type GaussianFilter struct {
+	radius float64
+	alpha  float64
+	edge   float64
+}
+
+// NewGaussianFilter constructs a GaussianFilter
+func NewGaussianFilter(radius, alpha float64) GaussianFilter {
+	return GaussianFilter{
+		radius: radius,
+		alpha:  alpha,
+		edge:   math.Exp(-alpha * radius * radius),
+	}
+}
+
+// Radius returns the filter radius
+func (f GaussianFilter) Radius() float64 {
+	return f.radius
+}
+
+// Weight returns the product of the gaussian on both axes
+func (f GaussianFilter) Weight(dx, dy float64) float64 {
+	return f.gaussian(dx) * f.gaussian(dy)
+}
+
+func (f GaussianFilter) gaussian(d float64) float64 {
+	return math.Max(0, math.Exp(-f.alpha*d*d)-f.edge)
+}
+
+// MitchellFilter is the Mitchell-Netravali cubic filter.
+// It has small negative lobes which sharpen edges,
+// and is a good default when trading off blurring against ringing.
+type MitchellFilter struct {
+	radius float64
+	b      float64
+	c      float64
+}
+
+// NewMitchellFilter constructs a MitchellFilter.
+// Mitchell and Netravali recommend b = c = 1/3.
+func NewMitchellFilter(radius, b, c float64) MitchellFilter {
+	return MitchellFilter{
+		radius: radius,
+		b:      b,
+		c:      c,
+	}
+}
+
+// Radius returns the filter radius
+func (f MitchellFilter) Radius() float64 {
+	return f.radius
+}
+
+// Weight returns the product of the cubic on both axes
+func (f MitchellFilter) Weight(dx, dy float64) float64 {
+	return f.mitchell(dx/f.radius) * f.mitchell(dy/f.radius)
+}
+
+// mitchell evaluates the cubic for x in [-1, 1],
+// which is stretched over its natural [-2, 2] domain.
+func (f MitchellFilter) mitchell(x float64) float64 {
+	b, c := f.b, f.c
+	x = math.Abs(2 * x)
+	if x < 1 {
+		return ((12-9*b-6*c)*x*x*x + (-18+12*b+6*c)*x*x + (6 - 2*b)) / 6
+	}
+	if x < 2 {
+		return ((-b-6*c)*x*x*x + (6*b+30*c)*x*x + (-12*b-48*c)*x + (8*b + 24*c)) / 6
+	}
+	return 0
+}
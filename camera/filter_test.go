@@ -0,0 +1,52 @@
+package camera
+
+import (
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := NewBoxFilter(0.5)
+	is.Equal(0.5, f.Radius())
+	is.Equal(1.0, f.Weight(0, 0))
+	is.Equal(1.0, f.Weight(0.4, -0.4))
+	is.Equal(0.0, f.Weight(0.6, 0))
+}
+
+func TestTentFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := NewTentFilter(1)
+	is.Equal(1.0, f.Radius())
+	is.Equal(1.0, f.Weight(0, 0))
+	is.Equal(0.25, f.Weight(0.5, -0.5))
+	is.Equal(0.0, f.Weight(1, 0))
+}
+
+func TestGaussianFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := NewGaussianFilter(1.5, 2)
+	is.Equal(1.5, f.Radius())
+	is.True(f.Weight(0, 0) > f.Weight(0.5, 0))
+	is.True(f.Weight(0.5, 0) > f.Weight(1, 0))
+	is.True(float.Equal(0, f.Weight(1.5, 0)))
+	is.Equal(f.Weight(0.5, 0), f.Weight(0, -0.5))
+}
+
+func TestMitchellFilter(t *testing.T) {
+	is := assert.New(t)
+
+	f := NewMitchellFilter(2, 1.0/3, 1.0/3)
+	is.Equal(2.0, f.Radius())
+	is.True(float.Equal(16.0/18*16.0/18, f.Weight(0, 0)))
+	is.True(float.Equal(0, f.Weight(2, 0)))
+
+	// the negative lobe
+	is.True(f.Weight(1.5, 0) < 0)
+}
@@ -0,0 +1,156 @@
+package camera
+
+import (
+	"errors"
+	"math"
+
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Projection turns a position on the canvas into a ray in camera space,
+// where the camera sits at the origin looking towards -z, with +y up
+// and +x to the left.
+// x and y are continuous canvas positions, where the center of pixel
+// (0, 0) is at (0.5, 0.5), on a canvas of hsize by vsize pixels.
+// It returns false for positions the projection does not cover,
+// which are rendered black.
+type Projection interface {
+	Ray(x, y float64, hsize, vsize int) (ray.Ray, bool)
+}
+
+// Perspective is a pinhole camera, where objects shrink with distance.
+// FieldOfView is the angle, in radians, across the longer side of the
+// canvas, and must be between 0 and π. The canvas sits one unit in
+// front of the camera. Shift slides it sideways by that many units, to
+// the left, to frame the view off center without turning the camera,
+// as the eyes of a Stereo do. Use NewPerspective to check the field of view.
+type Perspective struct {
+	FieldOfView float64
+	Shift       float64
+}
+
+// NewPerspective returns a Perspective projection,
+// or an error if the field of view is not between 0 and π.
+func NewPerspective(fieldOfView float64) (Perspective, error) {
+	if fieldOfView <= 0 || fieldOfView >= math.Pi {
+		return Perspective{}, errors.New("perspective field of view must be between 0 and π")
+	}
+	return Perspective{FieldOfView: fieldOfView}, nil
+}
+
+// PixelSize returns the size of a single pixel on the canvas,
+// in world space units, for a canvas of hsize by vsize pixels.
+func (p Perspective) PixelSize(hsize, vsize int) float64 {
+	halfWidth, _ := p.halfSize(hsize, vsize)
+	return halfWidth * 2 / float64(hsize)
+}
+
+// Ray returns the ray through the canvas position
+func (p Perspective) Ray(x, y float64, hsize, vsize int) (ray.Ray, bool) {
+	halfWidth, halfHeight := p.halfSize(hsize, vsize)
+	pixelSize := halfWidth * 2 / float64(hsize)
+
+	// the camera looks toward -z, so +x is to the left
+	worldX := halfWidth - x*pixelSize + p.Shift
+	worldY := halfHeight - y*pixelSize
+	return ray.New(tuple.Pt(0, 0, 0), tuple.Vec(worldX, worldY, -1).Normalize()), true
+}
+
+// halfSize returns half the width and height of the canvas
+func (p Perspective) halfSize(hsize, vsize int) (float64, float64) {
+	halfView := math.Tan(p.FieldOfView / 2)
+	aspect := float64(hsize) / float64(vsize)
+	if aspect >= 1 {
+		return halfView, halfView / aspect
+	}
+	return halfView * aspect, halfView
+}
+
+// Orthographic casts parallel rays, so that objects keep their size
+// however far away they are, as in technical drawings.
+// Width is the width of the view in world space units, and the height
+// follows from the aspect ratio of the canvas. Rays start on the
+// camera's z = 0 plane, so objects behind it are not seen.
+// Width must be greater than 0, which NewOrthographic checks.
+type Orthographic struct {
+	Width float64
+}
+
+// NewOrthographic returns an Orthographic projection,
+// or an error if the width is not greater than 0.
+func NewOrthographic(width float64) (Orthographic, error) {
+	if width <= 0 {
+		return Orthographic{}, errors.New("orthographic width must be greater than 0")
+	}
+	return Orthographic{Width: width}, nil
+}
+
+// Ray returns the ray through the canvas position
+func (o Orthographic) Ray(x, y float64, hsize, vsize int) (ray.Ray, bool) {
+	pixelSize := o.Width / float64(hsize)
+	worldX := o.Width/2 - x*pixelSize
+	worldY := pixelSize*float64(vsize)/2 - y*pixelSize
+	return ray.New(tuple.Pt(worldX, worldY, 0), tuple.Vec(0, 0, -1)), true
+}
+
+// Fisheye is an equidistant fisheye lens, where the distance from the
+// center of the image is proportional to the angle from the view
+// direction. FieldOfView is the angle, in radians, across the image
+// circle, which fills the shorter side of the canvas. It can be wider
+// than π to see behind the camera, up to 2π. The corners outside the
+// circle are black. Use NewFisheye to check the field of view.
+type Fisheye struct {
+	FieldOfView float64
+}
+
+// NewFisheye returns a Fisheye projection,
+// or an error if the field of view is not between 0 and 2π.
+func NewFisheye(fieldOfView float64) (Fisheye, error) {
+	if fieldOfView <= 0 || fieldOfView > 2*math.Pi {
+		return Fisheye{}, errors.New("fisheye field of view must be between 0 and 2π")
+	}
+	return Fisheye{FieldOfView: fieldOfView}, nil
+}
+
+// Ray returns the ray through the canvas position
+func (f Fisheye) Ray(x, y float64, hsize, vsize int) (ray.Ray, bool) {
+	radius := math.Min(float64(hsize), float64(vsize)) / 2
+	dx := (float64(hsize)/2 - x) / radius
+	dy := (float64(vsize)/2 - y) / radius
+	r := math.Hypot(dx, dy)
+	if r > 1 {
+		return ray.Ray{}, false
+	}
+	if r == 0 {
+		return ray.New(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, -1)), true
+	}
+	sin, cos := math.Sincos(r * f.FieldOfView / 2)
+	direction := tuple.Vec(sin*dx/r, sin*dy/r, -cos)
+	return ray.New(tuple.Pt(0, 0, 0), direction), true
+}
+
+// Panorama sees every direction around the camera, with longitude
+// running across the canvas and latitude up it, for 360° previews.
+// The center of the canvas is the view direction. The canvas should
+// be twice as wide as it is tall to keep the pixels square.
+// EyeOffset moves the origin of every ray sideways, to the left of its
+// direction, for omni-directional stereo. The eye circles the camera as
+// the view turns, so each direction is seen with the same parallax.
+// Positive offsets are the left eye and negative ones the right eye.
+type Panorama struct {
+	EyeOffset float64
+}
+
+// Ray returns the ray through the canvas position
+func (p Panorama) Ray(x, y float64, hsize, vsize int) (ray.Ray, bool) {
+	longitude := 2 * math.Pi * (0.5 - x/float64(hsize))
+	latitude := math.Pi * (0.5 - y/float64(vsize))
+	sinLon, cosLon := math.Sincos(longitude)
+	sinLat, cosLat := math.Sincos(latitude)
+	direction := tuple.Vec(sinLon*cosLat, sinLat, -cosLon*cosLat)
+
+	// the horizontal direction to the left of the view
+	origin := tuple.Pt(cosLon*p.EyeOffset, 0, sinLon*p.EyeOffset)
+	return ray.New(origin, direction), true
+}
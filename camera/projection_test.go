@@ -0,0 +1,174 @@
+package camera
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerspective(t *testing.T) {
+	is := assert.New(t)
+
+	p, err := NewPerspective(math.Pi / 2)
+	is.NoError(err)
+	r, ok := p.Ray(100.5, 50.5, 201, 101)
+	is.True(ok)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+
+	// shifting the canvas frames the view off center
+	p.Shift = 0.5
+	r, _ = p.Ray(100.5, 50.5, 201, 101)
+	is.True(r.Direction.Equal(tuple.Vec(0.5, 0, -1).Normalize()))
+
+	for _, fov := range []float64{0, -1, math.Pi} {
+		_, err := NewPerspective(fov)
+		is.Error(err)
+	}
+}
+
+func TestProjectionValidation(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := NewOrthographic(2)
+	is.NoError(err)
+	_, err = NewOrthographic(0)
+	is.Error(err)
+
+	_, err = NewFisheye(2 * math.Pi)
+	is.NoError(err)
+	_, err = NewFisheye(0)
+	is.Error(err)
+	_, err = NewFisheye(7)
+	is.Error(err)
+
+	// a camera cannot cast rays without a projection
+	c := New(10, 10, math.Pi/2)
+	c.Projection = nil
+	_, err = c.RayForPixel(5, 5)
+	is.EqualError(err, "camera has no projection")
+	_, err = c.Render(context.Background(), func(r ray.Ray) canvas.Color {
+		return canvas.NewColor(1, 1, 1)
+	}, nil)
+	is.Error(err)
+}
+
+func TestOrthographic(t *testing.T) {
+	is := assert.New(t)
+
+	o := Orthographic{Width: 4}
+	r, ok := o.Ray(100, 50, 200, 100)
+	is.True(ok)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+
+	// the corners of the canvas are the corners of the view
+	r, ok = o.Ray(0, 0, 200, 100)
+	is.True(ok)
+	is.True(r.Origin.Equal(tuple.Pt(2, 1, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+}
+
+func TestFisheye(t *testing.T) {
+	is := assert.New(t)
+
+	f := Fisheye{FieldOfView: math.Pi}
+	r, ok := f.Ray(50, 50, 100, 100)
+	is.True(ok)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+
+	// a 180° lens sees straight out to the side at the edge of the circle
+	r, ok = f.Ray(0, 50, 100, 100)
+	is.True(ok)
+	is.True(r.Direction.Equal(tuple.Vec(1, 0, 0)))
+
+	// angles grow evenly with the distance from the center
+	r, ok = f.Ray(50, 25, 100, 100)
+	is.True(ok)
+	is.True(r.Direction.Equal(tuple.Vec(0, math.Sqrt(0.5), -math.Sqrt(0.5))))
+
+	// the circle fits the shorter side, leaving the corners out
+	_, ok = f.Ray(0, 0, 100, 100)
+	is.False(ok)
+	_, ok = f.Ray(25, 50, 200, 100)
+	is.False(ok)
+}
+
+func TestPanorama(t *testing.T) {
+	is := assert.New(t)
+
+	p := Panorama{}
+	r, ok := p.Ray(100, 50, 200, 100)
+	is.True(ok)
+	is.True(r.Origin.Equal(tuple.Pt(0, 0, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+
+	// a quarter of the way across is a quarter turn to the left
+	r, _ = p.Ray(50, 50, 200, 100)
+	is.True(r.Direction.Equal(tuple.Vec(1, 0, 0)))
+	r, _ = p.Ray(0, 50, 200, 100)
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, 1)))
+
+	// which is the same side as the perspective camera
+	r, _ = New(200, 100, math.Pi/2).RayForPixel(0, 50)
+	is.True(r.Direction.X > 0)
+	r, _ = p.Ray(100, 0, 200, 100)
+	is.True(r.Direction.Equal(tuple.Vec(0, 1, 0)))
+}
+
+func TestCameraProjection(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(201, 101, math.Pi/2)
+	c.Transform = matrix.RotationY(math.Pi/2).Translate(0, -2, 5)
+
+	c.Projection = Panorama{}
+	r, err := c.RayForPixel(100, 50)
+	is.NoError(err)
+	is.True(r.Origin.Equal(tuple.Pt(5, 2, 0)))
+	is.True(r.Direction.Equal(tuple.Vec(1, 0, 0)))
+
+	c.Projection = Orthographic{Width: 4}
+	r, err = c.RayForPixel(0, 0)
+	is.NoError(err)
+	is.True(float.Equal(1, r.Direction.Magnitude()))
+
+	c.Projection = Fisheye{FieldOfView: math.Pi}
+	_, err = c.RayForPixel(0, 0)
+	is.Error(err)
+
+	// the lens works the same for every projection
+	c = New(201, 101, math.Pi/2)
+	c.Projection = Orthographic{Width: 4}
+	c.FocalDistance = 3
+	inv, err := c.inverse()
+	is.NoError(err)
+	pinhole, _ := c.rayAt(inv, 20.5, 30.5, 0, 0)
+	lens, ok := c.rayAt(inv, 20.5, 30.5, 0.25, -0.1)
+	is.True(ok)
+	is.True(float.Equal(1, lens.Direction.Magnitude()))
+	is.True(lens.Position(-3 / lens.Direction.Z).Equal(pinhole.Position(3)))
+}
+
+func TestRenderFisheye(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(11, 11, math.Pi/2)
+	c.Projection = Fisheye{FieldOfView: math.Pi}
+	white := func(r ray.Ray) canvas.Color {
+		return canvas.NewColor(1, 1, 1)
+	}
+	img, err := c.Render(context.Background(), white, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(5, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(0, 0).Equal(canvas.NewColor(0, 0, 0)))
+}
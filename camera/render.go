@@ -0,0 +1,155 @@
+package camera
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/logging"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/ray"
+)
+
+// TileSize is the width and height, in pixels, of the
+// square tiles a render is broken up into.
+const TileSize = 16
+
+// TraceFunc returns the color seen along a single ray.
+type TraceFunc func(r ray.Ray) canvas.Color
+
+// Progress describes how far along a render is.
+// It is handed to the ProgressFunc after every finished tile.
+type Progress struct {
+	TilesDone  int
+	TilesTotal int
+	RaysCast   int
+	Elapsed    time.Duration
+	ETA        time.Duration
+}
+
+// ProgressFunc receives progress events during a render.
+type ProgressFunc func(p Progress)
+
+// Render casts rays through every pixel of the camera, as placed by the
+// Sampler, and writes the filtered colors to a new canvas.
+// The render stops between pixels as soon as ctx is cancelled, in which
+// case the partially rendered canvas is returned along with ctx.Err().
+// progress may be nil.
+func (c Camera) Render(ctx context.Context, trace TraceFunc, progress ProgressFunc) (canvas.Canvas, error) {
+	img, _, err := c.RenderHeatMap(ctx, trace, progress)
+	return img, err
+}
+
+// RenderHeatMap renders like Render, and also returns a heat map of
+// how many rays were cast through each pixel. This is mostly useful
+// for tuning the camera's Adaptive settings.
+func (c Camera) RenderHeatMap(ctx context.Context, trace TraceFunc, progress ProgressFunc) (canvas.Canvas, canvas.Canvas, error) {
+	log := logging.OrDiscard(c.Logger)
+	img := canvas.NewCanvas(c.HSize, c.VSize)
+	img.Logger = c.Logger
+	counts := make([][]int, c.VSize)
+	for y := range counts {
+		counts[y] = make([]int, c.HSize)
+	}
+
+	inv, err := c.inverse()
+	if err != nil {
+		log.Error("camera transform is not invertible", "err", err)
+		return img, heatMap(counts), err
+	}
+
+	sampler, filter := c.antiAliasing()
+	rnd := rand.New(rand.NewSource(c.Seed))
+	f := newFilm(c.HSize, c.VSize, filter)
+
+	tilesX := (c.HSize + TileSize - 1) / TileSize
+	tilesY := (c.VSize + TileSize - 1) / TileSize
+	p := Progress{TilesTotal: tilesX * tilesY}
+	start := time.Now()
+	log.Info("render started", "width", c.HSize, "height", c.VSize, "tiles", p.TilesTotal)
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			for y := ty * TileSize; y < (ty+1)*TileSize && y < c.VSize; y++ {
+				for x := tx * TileSize; x < (tx+1)*TileSize && x < c.HSize; x++ {
+					if err := ctx.Err(); err != nil {
+						log.Info("render cancelled", "tiles_done", p.TilesDone, "err", err)
+						f.develop(img)
+						return img, heatMap(counts), err
+					}
+					n := c.samplePixel(inv, x, y, sampler, rnd, f, trace)
+					counts[y][x] = n
+					p.RaysCast += n
+				}
+			}
+			p.TilesDone++
+			p.Elapsed = time.Since(start)
+			p.ETA = eta(p)
+			log.Debug("tile finished", "tiles_done", p.TilesDone, "rays_cast", p.RaysCast, "eta", p.ETA)
+			if progress != nil {
+				progress(p)
+			}
+		}
+	}
+	f.develop(img)
+	log.Info("render finished", "rays_cast", p.RaysCast, "elapsed", time.Since(start))
+	return img, heatMap(counts), nil
+}
+
+// samplePixel casts passes of the sampler through a single pixel until
+// the Adaptive settings are satisfied, and returns how many rays it cast.
+func (c Camera) samplePixel(inv matrix.Mat4, x, y int, sampler Sampler, rnd *rand.Rand, f film, trace TraceFunc) int {
+	var stats pixelStats
+	min, max := c.Adaptive.passes()
+	for pass := 0; pass < max; pass++ {
+		if pass >= min && c.Adaptive.converged(stats) {
+			break
+		}
+		for _, o := range sampler.Samples(rnd) {
+			sx, sy := float64(x)+0.5+o.X, float64(y)+0.5+o.Y
+			lx, ly := c.lens(rnd)
+			color := canvas.NewColor(0, 0, 0)
+			if r, ok := c.rayAt(inv, sx, sy, lx, ly); ok {
+				r.Time = c.shutter(rnd)
+				color = trace(r)
+			}
+			f.addSample(sx, sy, color)
+			stats.add(color)
+		}
+	}
+	return stats.n
+}
+
+// antiAliasing returns the camera's Sampler and Filter,
+// falling back to one sample per pixel and a box filter.
+// Adaptive passes of a RegularGrid or RotatedGrid would cast the same
+// rays over and over, so they are jittered instead.
+func (c Camera) antiAliasing() (Sampler, Filter) {
+	sampler, filter := c.Sampler, c.Filter
+	if sampler == nil {
+		sampler = RegularGrid{N: 1}
+	}
+	if c.Adaptive != nil {
+		switch s := sampler.(type) {
+		case RegularGrid:
+			sampler = Jittered{N: s.N}
+		case RotatedGrid:
+			sampler = Jittered{N: s.N}
+		}
+	}
+	if filter == nil {
+		filter = NewBoxFilter(0.5)
+	}
+	return sampler, filter
+}
+
+// eta extrapolates the remaining render time from the
+// average time taken per finished tile.
+func eta(p Progress) time.Duration {
+	if p.TilesDone == 0 {
+		return 0
+	}
+	perTile := p.Elapsed / time.Duration(p.TilesDone)
+	return perTile * time.Duration(p.TilesTotal-p.TilesDone)
+}
@@ -0,0 +1,226 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/logging"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// directionColor shades each ray by the absolute value of its direction
+func directionColor(r ray.Ray) canvas.Color {
+	return canvas.NewColor(math.Abs(r.Direction.X), math.Abs(r.Direction.Y), math.Abs(r.Direction.Z))
+}
+
+func TestRender(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(11, 11, math.Pi/2)
+	img, err := c.Render(context.Background(), directionColor, nil)
+	is.NoError(err)
+	is.Equal(11, img.Width)
+	is.Equal(11, img.Height)
+	is.True(img.PixelAt(5, 5).Equal(canvas.NewColor(0, 0, 1)))
+}
+
+func TestRenderReportsProgress(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(40, 20, math.Pi/2)
+	var events []Progress
+	_, err := c.Render(context.Background(), directionColor, func(p Progress) {
+		events = append(events, p)
+	})
+	is.NoError(err)
+
+	// 40x20 pixels is 3x2 tiles of 16x16
+	is.Len(events, 6)
+	for i, p := range events {
+		is.Equal(i+1, p.TilesDone)
+		is.Equal(6, p.TilesTotal)
+	}
+	last := events[len(events)-1]
+	is.Equal(40*20, last.RaysCast)
+	is.Equal(int64(0), int64(last.ETA))
+}
+
+func TestRenderCancelled(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(40, 20, math.Pi/2)
+	ctx, cancel := context.WithCancel(context.Background())
+	rays := 0
+	_, err := c.Render(ctx, func(r ray.Ray) canvas.Color {
+		rays++
+		if rays == 10 {
+			cancel()
+		}
+		return directionColor(r)
+	}, nil)
+	is.Equal(context.Canceled, err)
+	is.Equal(10, rays)
+}
+
+func TestRenderLogs(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	c := New(4, 4, math.Pi/2)
+	c.Logger = logging.New(&buf, false)
+
+	_, err := c.Render(context.Background(), directionColor, nil)
+	is.NoError(err)
+	is.Contains(buf.String(), `msg="render started" width=4 height=4 tiles=1`)
+	is.Contains(buf.String(), `msg="render finished" rays_cast=16`)
+}
+
+func TestRenderAntiAliased(t *testing.T) {
+	is := assert.New(t)
+
+	// everything to the left of the center line is white
+	edge := func(r ray.Ray) canvas.Color {
+		if r.Direction.X > 0 {
+			return canvas.NewColor(1, 1, 1)
+		}
+		return canvas.NewColor(0, 0, 0)
+	}
+
+	c := New(11, 11, math.Pi/2)
+	img, err := c.Render(context.Background(), edge, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(5, 5).Equal(canvas.NewColor(0, 0, 0)))
+
+	c.Sampler = RegularGrid{N: 2}
+	img, err = c.Render(context.Background(), edge, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(4, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(5, 5).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	is.True(img.PixelAt(6, 5).Equal(canvas.NewColor(0, 0, 0)))
+
+	c.Sampler = Jittered{N: 4}
+	c.Filter = NewMitchellFilter(2, 1.0/3, 1.0/3)
+	var rays int
+	img, err = c.Render(context.Background(), edge, func(p Progress) {
+		rays = p.RaysCast
+	})
+	is.NoError(err)
+	is.Equal(11*11*16, rays)
+	is.True(img.PixelAt(0, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(10, 5).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(img.PixelAt(5, 5).R() > 0 && img.PixelAt(5, 5).R() < 1)
+}
+
+func TestRenderAdaptive(t *testing.T) {
+	is := assert.New(t)
+
+	edge := func(r ray.Ray) canvas.Color {
+		if r.Direction.X > 0 {
+			return canvas.NewColor(1, 1, 1)
+		}
+		return canvas.NewColor(0, 0, 0)
+	}
+
+	c := New(11, 11, math.Pi/2)
+	c.Sampler = Jittered{N: 2}
+	c.Adaptive = &Adaptive{MinPasses: 2, MaxPasses: 8, Threshold: 0.01}
+
+	var rays int
+	img, heat, err := c.RenderHeatMap(context.Background(), edge, func(p Progress) {
+		rays = p.RaysCast
+	})
+	is.NoError(err)
+
+	// the pixel straddling the edge never converges and gets every pass,
+	// while flat pixels stop after the minimum two passes
+	is.True(heat.PixelAt(5, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(heat.PixelAt(0, 5).Equal(canvas.NewColor(0.25, 0.25, 0.25)))
+	is.True(heat.PixelAt(10, 5).Equal(canvas.NewColor(0.25, 0.25, 0.25)))
+	is.Equal(11*(10*8+32), rays)
+
+	is.True(img.PixelAt(0, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(5, 5).R() > 0 && img.PixelAt(5, 5).R() < 1)
+}
+
+func TestRenderAdaptiveJittersGrids(t *testing.T) {
+	is := assert.New(t)
+
+	for _, sampler := range []Sampler{nil, RegularGrid{N: 2}, RotatedGrid{N: 2}} {
+		c := New(1, 1, math.Pi/2)
+		c.Sampler = sampler
+		c.Adaptive = &Adaptive{MinPasses: 4, MaxPasses: 4, Threshold: 0.01}
+
+		// every pass casts new rays, rather than repeating the grid
+		seen := map[tuple.Vector]bool{}
+		_, err := c.Render(context.Background(), func(r ray.Ray) canvas.Color {
+			seen[r.Direction] = true
+			return canvas.NewColor(0, 0, 0)
+		}, nil)
+		is.NoError(err)
+		n := 1
+		if sampler != nil {
+			n = 4
+		}
+		is.Len(seen, 4*n, "%#v", sampler)
+	}
+}
+
+func TestRenderDepthOfField(t *testing.T) {
+	is := assert.New(t)
+
+	// a wall four units away, white to the left of the center line
+	wall := func(r ray.Ray) canvas.Color {
+		if r.Position((-4-r.Origin.Z)/r.Direction.Z).X > 0 {
+			return canvas.NewColor(1, 1, 1)
+		}
+		return canvas.NewColor(0, 0, 0)
+	}
+
+	c := New(11, 11, math.Pi/2)
+	c.Sampler = RegularGrid{N: 4}
+	c.Aperture = 2
+	c.FocalDistance = 4
+	img, err := c.Render(context.Background(), wall, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(4, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(6, 5).Equal(canvas.NewColor(0, 0, 0)))
+
+	// focused in front of the wall, the edge spreads over its neighbours
+	c.FocalDistance = 1
+	img, err = c.Render(context.Background(), wall, nil)
+	is.NoError(err)
+	for _, x := range []int{4, 6} {
+		is.True(img.PixelAt(x, 5).R() > 0 && img.PixelAt(x, 5).R() < 1)
+	}
+}
+
+func TestRenderMotionBlur(t *testing.T) {
+	is := assert.New(t)
+
+	// a white wall slides left across the view while the shutter is open
+	moving := func(r ray.Ray) canvas.Color {
+		if r.Direction.X > r.Time-0.5 {
+			return canvas.NewColor(1, 1, 1)
+		}
+		return canvas.NewColor(0, 0, 0)
+	}
+
+	c := New(11, 11, math.Pi/2)
+	img, err := c.Render(context.Background(), moving, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(5, 5).Equal(canvas.NewColor(1, 1, 1)))
+
+	c.Sampler = Jittered{N: 4}
+	c.ShutterClose = 1
+	img, err = c.Render(context.Background(), moving, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(0, 5).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(img.PixelAt(10, 5).Equal(canvas.NewColor(0, 0, 0)))
+	is.InDelta(0.5, img.PixelAt(5, 5).R(), 0.25)
+}
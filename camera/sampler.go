@@ -0,0 +1,95 @@
+package camera
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Offset is a sample position relative to the center of a pixel,
+// in pixel units. Both X and Y fall within [-0.5, 0.5).
+type Offset struct {
+	X float64
+	Y float64
+}
+
+// Sampler decides where within a pixel rays are cast.
+type Sampler interface {
+	Samples(rnd *rand.Rand) []Offset
+}
+
+// RegularGrid places N x N samples on an evenly spaced grid
+type RegularGrid struct {
+	N int
+}
+
+// Samples returns the grid positions, rnd is unused
+func (g RegularGrid) Samples(rnd *rand.Rand) []Offset {
+	return grid(g.N, func(i, j, n float64) Offset {
+		return Offset{
+			X: (i+0.5)/n - 0.5,
+			Y: (j+0.5)/n - 0.5,
+		}
+	})
+}
+
+// Jittered splits the pixel into N x N strata and
+// places one sample at a random position within each of them.
+type Jittered struct {
+	N int
+}
+
+// Samples returns one randomly jittered position per stratum
+func (g Jittered) Samples(rnd *rand.Rand) []Offset {
+	return grid(g.N, func(i, j, n float64) Offset {
+		return Offset{
+			X: (i+rnd.Float64())/n - 0.5,
+			Y: (j+rnd.Float64())/n - 0.5,
+		}
+	})
+}
+
+// rotatedGridAngle is the angle the classic 4x rotated grid
+// pattern is rotated by. It gives every sample a distinct
+// position when projected onto either axis.
+var rotatedGridAngle = math.Atan(0.5)
+
+// RotatedGrid is a RegularGrid rotated by atan(1/2),
+// with samples that fall outside of the pixel wrapped back into it.
+// It handles near-horizontal and near-vertical edges better
+// than a RegularGrid with the same number of samples.
+type RotatedGrid struct {
+	N int
+}
+
+// Samples returns the rotated grid positions, rnd is unused
+func (g RotatedGrid) Samples(rnd *rand.Rand) []Offset {
+	sin, cos := math.Sincos(rotatedGridAngle)
+	return grid(g.N, func(i, j, n float64) Offset {
+		x := (i+0.5)/n - 0.5
+		y := (j+0.5)/n - 0.5
+		return Offset{
+			X: wrap(x*cos - y*sin),
+			Y: wrap(x*sin + y*cos),
+		}
+	})
+}
+
+// grid calls fn with the column, row and size of every cell of an n x n grid.
+// An n of less than one is treated as one.
+func grid(n int, fn func(i, j, n float64) Offset) []Offset {
+	if n < 1 {
+		n = 1
+	}
+	samples := make([]Offset, 0, n*n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			samples = append(samples, fn(float64(i), float64(j), float64(n)))
+		}
+	}
+	return samples
+}
+
+// wrap moves an offset back into [-0.5, 0.5)
+func wrap(f float64) float64 {
+	return f - math.Floor(f+0.5)
+}
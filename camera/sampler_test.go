@@ -0,0 +1,64 @@
+package camera
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inPixel(is *assert.Assertions, samples []Offset) {
+	for _, s := range samples {
+		is.True(s.X >= -0.5 && s.X < 0.5, "x offset %f outside of pixel", s.X)
+		is.True(s.Y >= -0.5 && s.Y < 0.5, "y offset %f outside of pixel", s.Y)
+	}
+}
+
+func TestRegularGrid(t *testing.T) {
+	is := assert.New(t)
+
+	samples := RegularGrid{N: 2}.Samples(nil)
+	is.Equal([]Offset{
+		{X: -0.25, Y: -0.25},
+		{X: 0.25, Y: -0.25},
+		{X: -0.25, Y: 0.25},
+		{X: 0.25, Y: 0.25},
+	}, samples)
+
+	is.Equal([]Offset{{X: 0, Y: 0}}, RegularGrid{}.Samples(nil))
+}
+
+func TestJittered(t *testing.T) {
+	is := assert.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	samples := Jittered{N: 4}.Samples(rnd)
+	is.Len(samples, 16)
+	inPixel(is, samples)
+
+	// every sample stays within its own stratum
+	for i, s := range samples {
+		col, row := i%4, i/4
+		is.True(s.X+0.5 >= float64(col)/4 && s.X+0.5 < float64(col+1)/4)
+		is.True(s.Y+0.5 >= float64(row)/4 && s.Y+0.5 < float64(row+1)/4)
+	}
+	is.NotEqual(samples, Jittered{N: 4}.Samples(rnd))
+}
+
+func TestRotatedGrid(t *testing.T) {
+	is := assert.New(t)
+
+	samples := RotatedGrid{N: 2}.Samples(nil)
+	is.Len(samples, 4)
+	inPixel(is, samples)
+
+	// no two samples share a row or a column
+	xs := map[float64]bool{}
+	ys := map[float64]bool{}
+	for _, s := range samples {
+		xs[s.X] = true
+		ys[s.Y] = true
+	}
+	is.Len(xs, 4)
+	is.Len(ys, 4)
+}
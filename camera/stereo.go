@@ -0,0 +1,137 @@
+package camera
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muzfuz/raytrace/canvas"
+)
+
+// Stereo renders the scene twice, from a left and a right eye either
+// side of the Camera, for viewing in 3D.
+// InterocularDistance is the distance between the eyes, in world space
+// units. Convergence is the distance from the camera at which the two
+// views line up, so objects there appear at the depth of the screen.
+// The views are shifted rather than turned towards each other, which
+// keeps their horizons level. When Convergence is 0 the views are
+// parallel and everything appears in front of the screen.
+// Convergence only applies to a Perspective projection.
+// A Panorama is rendered as omni-directional stereo, with the eyes
+// circling the camera so that every direction has the same parallax,
+// and converging at infinity. For the other projections the whole
+// camera is simply moved sideways, which is only correct for views
+// straight ahead.
+type Stereo struct {
+	Camera              Camera
+	InterocularDistance float64
+	Convergence         float64
+}
+
+// NewStereo returns a Stereo for the camera, with eyes interocular
+// apart that converge on objects convergence away.
+func NewStereo(c Camera, interocular, convergence float64) Stereo {
+	return Stereo{
+		Camera:              c,
+		InterocularDistance: interocular,
+		Convergence:         convergence,
+	}
+}
+
+// Eyes returns the cameras for the left and the right eye
+func (s Stereo) Eyes() (Camera, Camera) {
+	half := s.InterocularDistance / 2
+	shift := 0.0
+	if s.Convergence > 0 {
+		shift = half / s.Convergence
+	}
+
+	left, right := s.Camera, s.Camera
+	if p, ok := s.Camera.Projection.(Panorama); ok {
+		l, r := p, p
+		l.EyeOffset += half
+		r.EyeOffset -= half
+		left.Projection, right.Projection = l, r
+		return left, right
+	}
+
+	// the camera looks toward -z, so the left eye is towards +x
+	left.Transform = s.Camera.Transform.Translate(-half, 0, 0)
+	right.Transform = s.Camera.Transform.Translate(half, 0, 0)
+	if p, ok := s.Camera.Projection.(Perspective); ok {
+		l, r := p, p
+		l.Shift -= shift
+		r.Shift += shift
+		left.Projection, right.Projection = l, r
+	}
+	return left, right
+}
+
+// Render renders the view from each eye, left first, and returns the
+// left and right images. Progress covers both renders, as if they
+// were one twice the size.
+func (s Stereo) Render(ctx context.Context, trace TraceFunc, progress ProgressFunc) (canvas.Canvas, canvas.Canvas, error) {
+	leftEye, rightEye := s.Eyes()
+
+	var done, last Progress
+	report := func(p Progress) {
+		p.TilesDone += done.TilesDone
+		p.TilesTotal *= 2
+		p.RaysCast += done.RaysCast
+		p.Elapsed += done.Elapsed
+		p.ETA = eta(p)
+		last = p
+		if progress != nil {
+			progress(p)
+		}
+	}
+
+	left, err := leftEye.Render(ctx, trace, report)
+	if err != nil {
+		return left, canvas.NewCanvas(s.Camera.HSize, s.Camera.VSize), err
+	}
+	done = last
+	right, err := rightEye.Render(ctx, trace, report)
+	return left, right, err
+}
+
+// SideBySide places the left and right images next to each other,
+// left on the left, as expected by most VR viewers and 3D displays.
+func SideBySide(left, right canvas.Canvas) (canvas.Canvas, error) {
+	if err := sameSize(left, right); err != nil {
+		return canvas.Canvas{}, err
+	}
+	img := canvas.NewCanvas(left.Width*2, left.Height)
+	img.Logger = left.Logger
+	for y := 0; y < left.Height; y++ {
+		for x := 0; x < left.Width; x++ {
+			img.WritePixel(x, y, left.PixelAt(x, y))
+			img.WritePixel(left.Width+x, y, right.PixelAt(x, y))
+		}
+	}
+	return img, nil
+}
+
+// Anaglyph combines the left and right images into one, for red/cyan
+// glasses. The red channel comes from the left image and the green and
+// blue channels from the right, so some of the colors survive.
+func Anaglyph(left, right canvas.Canvas) (canvas.Canvas, error) {
+	if err := sameSize(left, right); err != nil {
+		return canvas.Canvas{}, err
+	}
+	img := canvas.NewCanvas(left.Width, left.Height)
+	img.Logger = left.Logger
+	for y := 0; y < left.Height; y++ {
+		for x := 0; x < left.Width; x++ {
+			l, r := left.PixelAt(x, y), right.PixelAt(x, y)
+			img.WritePixel(x, y, canvas.NewColor(l.R(), r.G(), r.B()))
+		}
+	}
+	return img, nil
+}
+
+func sameSize(left, right canvas.Canvas) error {
+	if left.Width != right.Width || left.Height != right.Height {
+		return errors.New("left and right images are different sizes")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package camera
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStereoEyes(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(11, 11, math.Pi/2)
+	c.Transform = matrix.Translation(0, -2, 5)
+	s := NewStereo(c, 0.5, 4)
+	left, right := s.Eyes()
+
+	// both eyes look through their center pixel at the point they converge on
+	l, err := left.RayForPixel(5, 5)
+	is.NoError(err)
+	r, err := right.RayForPixel(5, 5)
+	is.NoError(err)
+	is.True(l.Origin.Equal(tuple.Pt(0.25, 2, -5)))
+	is.True(r.Origin.Equal(tuple.Pt(-0.25, 2, -5)))
+	is.True(l.Position(-4 / l.Direction.Z).Equal(tuple.Pt(0, 2, -9)))
+	is.True(r.Position(-4 / r.Direction.Z).Equal(tuple.Pt(0, 2, -9)))
+
+	// without convergence the eyes look straight ahead
+	s.Convergence = 0
+	left, right = s.Eyes()
+	l, _ = left.RayForPixel(5, 5)
+	r, _ = right.RayForPixel(5, 5)
+	is.True(l.Direction.Equal(tuple.Vec(0, 0, -1)))
+	is.True(r.Direction.Equal(tuple.Vec(0, 0, -1)))
+}
+
+func TestStereoPanorama(t *testing.T) {
+	is := assert.New(t)
+
+	c := New(200, 100, math.Pi/2)
+	c.Projection = Panorama{}
+	left, right := NewStereo(c, 0.5, 4).Eyes()
+
+	// looking ahead, to the side and behind, the left eye is always on
+	// the left of the view, the right eye on the right
+	for _, tt := range []struct {
+		x           int
+		left, right tuple.Point
+	}{
+		{x: 100, left: tuple.Pt(0.25, 0, 0), right: tuple.Pt(-0.25, 0, 0)},
+		{x: 50, left: tuple.Pt(0, 0, 0.25), right: tuple.Pt(0, 0, -0.25)},
+		{x: 0, left: tuple.Pt(-0.25, 0, 0), right: tuple.Pt(0.25, 0, 0)},
+	} {
+		l, err := left.RayForPixel(tt.x, 50)
+		is.NoError(err)
+		r, err := right.RayForPixel(tt.x, 50)
+		is.NoError(err)
+		is.True(l.Origin.EqualWithin(tt.left, float.Tolerance{Abs: 0.01}), "%d: %v", tt.x, l.Origin)
+		is.True(r.Origin.EqualWithin(tt.right, float.Tolerance{Abs: 0.01}), "%d: %v", tt.x, r.Origin)
+		is.True(l.Direction.Equal(r.Direction))
+	}
+}
+
+func TestStereoRender(t *testing.T) {
+	is := assert.New(t)
+
+	// a wall ten units away, white to the left of x = 0
+	edge := func(r ray.Ray) canvas.Color {
+		if r.Position((-10-r.Origin.Z)/r.Direction.Z).X > 0 {
+			return canvas.NewColor(1, 1, 1)
+		}
+		return canvas.NewColor(0, 0, 0)
+	}
+
+	s := NewStereo(New(40, 20, math.Pi/2), 2, 0)
+	var events []Progress
+	left, right, err := s.Render(context.Background(), edge, func(p Progress) {
+		events = append(events, p)
+	})
+	is.NoError(err)
+
+	// each eye is 3x2 tiles
+	is.Len(events, 12)
+	is.Equal(12, events[11].TilesDone)
+	is.Equal(12, events[11].TilesTotal)
+	is.Equal(2*40*20, events[11].RaysCast)
+
+	// the eyes see the edge from either side
+	is.True(left.PixelAt(20, 10).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(right.PixelAt(19, 10).Equal(canvas.NewColor(0, 0, 0)))
+}
+
+func TestSideBySide(t *testing.T) {
+	is := assert.New(t)
+
+	left := canvas.NewCanvas(2, 1)
+	left.WriteAllPixels(canvas.NewColor(1, 0, 0))
+	right := canvas.NewCanvas(2, 1)
+	right.WriteAllPixels(canvas.NewColor(0, 0, 1))
+
+	img, err := SideBySide(left, right)
+	is.NoError(err)
+	is.Equal(4, img.Width)
+	is.Equal(1, img.Height)
+	is.Equal(canvas.NewColor(1, 0, 0), img.PixelAt(1, 0))
+	is.Equal(canvas.NewColor(0, 0, 1), img.PixelAt(2, 0))
+
+	_, err = SideBySide(left, canvas.NewCanvas(2, 2))
+	is.Error(err)
+}
+
+func TestAnaglyph(t *testing.T) {
+	is := assert.New(t)
+
+	left := canvas.NewCanvas(2, 1)
+	left.WriteAllPixels(canvas.NewColor(0.2, 0.4, 0.6))
+	right := canvas.NewCanvas(2, 1)
+	right.WriteAllPixels(canvas.NewColor(0.7, 0.8, 0.9))
+
+	img, err := Anaglyph(left, right)
+	is.NoError(err)
+	is.Equal(2, img.Width)
+	is.Equal(canvas.NewColor(0.2, 0.8, 0.9), img.PixelAt(0, 0))
+
+	_, err = Anaglyph(left, canvas.NewCanvas(1, 1))
+	is.Error(err)
+}
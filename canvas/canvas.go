@@ -3,6 +3,8 @@ package canvas
 import (
 	"fmt"
 	"strings"
+
+	"github.com/muzfuz/raytrace/logging"
 )
 
 // Canvas is a rectangular grid of pixels
@@ -11,9 +13,11 @@ import (
 // If we want to visually represent our canvas, then
 // and writes to the Y coordinates have to be inverted
 // by subtracting their value from the height of the canvas.
+// Logger is optional, when it is nil the canvas logs nothing.
 type Canvas struct {
 	Width  int
 	Height int
+	Logger logging.Logger
 	pixels [][]Color
 }
 
@@ -30,12 +34,13 @@ func NewCanvas(w, h int) Canvas {
 	}
 }
 
-// WritePixel writes a color to a single pixel
+// WritePixel writes a color to a single pixel.
+// Writes outside of the canvas are ignored.
 func (c Canvas) WritePixel(x int, y int, color Color) {
 	if x > c.Width-1 || y > c.Height-1 || x < 0 || y < 0 {
+		logging.OrDiscard(c.Logger).Debug("pixel out of bounds", "x", x, "y", y)
 		return
 	}
-	fmt.Println(x, y)
 	c.pixels[y][x] = color
 }
 
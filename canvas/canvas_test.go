@@ -1,8 +1,11 @@
 package canvas
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/muzfuz/raytrace/logging"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,3 +70,18 @@ func TestPPMSplitLines(t *testing.T) {
 	ppm := canvas.ToPPM()
 	is.Equal(expected, ppm)
 }
+
+func TestWritePixelOutOfBounds(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	c := NewCanvas(10, 20)
+	c.Logger = logging.New(&buf, true)
+
+	c.WritePixel(10, 3, NewColor(1, 0, 0))
+	is.Contains(buf.String(), `msg="pixel out of bounds" x=10 y=3`)
+
+	buf.Reset()
+	c.WritePixel(2, 3, NewColor(1, 0, 0))
+	is.Empty(buf.String())
+}
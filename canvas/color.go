@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/muzfuz/raytrace/float"
 	"github.com/muzfuz/raytrace/tuple"
 )
 
@@ -15,7 +16,7 @@ type Color struct {
 // NewColor returns a struct
 func NewColor(r, g, b float64) Color {
 	return Color{
-		tuple.NewVector(r, g, b),
+		tuple.Vec(r, g, b).Tuple(),
 	}
 }
 
@@ -39,6 +40,12 @@ func (c Color) Equal(c2 Color) bool {
 	return c.Tuple.Equal(c2.Tuple)
 }
 
+// EqualWithin delegates to a Tuple for checking equality
+// using the given comparator
+func (c Color) EqualWithin(c2 Color, cmp float.Comparator) bool {
+	return c.Tuple.EqualWithin(c2.Tuple, cmp)
+}
+
 // Add delegates to a Tuple for adding values
 func (c Color) Add(c2 Color) Color {
 	t := c.Tuple.Add(c2.Tuple)
@@ -66,6 +73,12 @@ func (c Color) Multiply(c2 Color) Color {
 	)
 }
 
+// Luminance returns the brightness of the color as perceived
+// by the eye, using the Rec. 709 weights.
+func (c Color) Luminance() float64 {
+	return 0.2126*c.R() + 0.7152*c.G() + 0.0722*c.B()
+}
+
 // toRGBString converts float values into RGB pixel ints
 func (c Color) toRGBString() string {
 	return fmt.Sprintf("%d %d %d", toPixel(c.R()), toPixel(c.G()), toPixel(c.B()))
@@ -3,6 +3,8 @@ package canvas
 import (
 	"testing"
 
+	"github.com/muzfuz/raytrace/float"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +18,17 @@ func TestNewColor(t *testing.T) {
 	is.Equal(1.7, color.B())
 }
 
+func TestColorEqualWithin(t *testing.T) {
+	is := assert.New(t)
+
+	c1 := NewColor(0.5, 0.5, 0.5)
+	c2 := NewColor(0.51, 0.5, 0.5)
+
+	is.False(c1.Equal(c2))
+	is.True(c1.EqualWithin(c2, float.Tolerance{Abs: 0.02}))
+	is.False(c1.EqualWithin(c2, float.Tolerance{Abs: 0.001}))
+}
+
 func TestAddingColors(t *testing.T) {
 	is := assert.New(t)
 	expected := NewColor(1.6, 0.7, 1.0)
@@ -53,3 +66,13 @@ func TestMulitplyColors(t *testing.T) {
 
 	is.True(c1.Multiply(c2).Equal(expected))
 }
+
+func TestColorLuminance(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(float.Equal(1, NewColor(1, 1, 1).Luminance()))
+	is.True(float.Equal(0, NewColor(0, 0, 0).Luminance()))
+	// green looks much brighter than blue
+	is.True(NewColor(0, 1, 0).Luminance() > NewColor(0, 0, 1).Luminance())
+	is.True(float.Equal(0.2126*2, NewColor(2, 0, 0).Luminance()))
+}
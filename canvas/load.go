@@ -0,0 +1,96 @@
+package canvas
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	// register the image formats Load can decode
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Load reads a canvas from an image file on disk.
+// It reads plain PPM files, as written by ToPPM, as well as PNG and JPEG.
+func Load(path string) (Canvas, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Canvas{}, err
+	}
+	if bytes.HasPrefix(data, []byte("P3")) {
+		return ReadPPM(bytes.NewReader(data))
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Canvas{}, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	return FromImage(img), nil
+}
+
+// ReadPPM reads a canvas from a plain (P3) PPM image.
+func ReadPPM(r io.Reader) (Canvas, error) {
+	// comments run from a # to the end of the line
+	var fields []string
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		line := lines.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields = append(fields, strings.Fields(line)...)
+	}
+	if err := lines.Err(); err != nil {
+		return Canvas{}, err
+	}
+
+	if len(fields) < 4 || fields[0] != "P3" {
+		return Canvas{}, errors.New("not a plain PPM image")
+	}
+	header := make([]int, 3)
+	for i := range header {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil || n < 1 {
+			return Canvas{}, fmt.Errorf("invalid PPM header value %q", fields[i+1])
+		}
+		header[i] = n
+	}
+	w, h, max := header[0], header[1], float64(header[2])
+	values := fields[4:]
+	if len(values) != w*h*3 {
+		return Canvas{}, fmt.Errorf("expected %d PPM color values, got %d", w*h*3, len(values))
+	}
+
+	c := NewCanvas(w, h)
+	rgb := make([]float64, 3)
+	for i := 0; i < w*h; i++ {
+		for j := range rgb {
+			n, err := strconv.Atoi(values[i*3+j])
+			if err != nil {
+				return Canvas{}, fmt.Errorf("invalid PPM color value %q", values[i*3+j])
+			}
+			rgb[j] = float64(n) / max
+		}
+		c.WritePixel(i%w, i/w, NewColor(rgb[0], rgb[1], rgb[2]))
+	}
+	return c, nil
+}
+
+// FromImage copies an image into a new canvas,
+// with every channel scaled to between 0.0 and 1.0.
+func FromImage(img image.Image) Canvas {
+	b := img.Bounds()
+	c := NewCanvas(b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			c.WritePixel(x-b.Min.X, y-b.Min.Y, NewColor(float64(r)/0xffff, float64(g)/0xffff, float64(bl)/0xffff))
+		}
+	}
+	return c
+}
@@ -0,0 +1,124 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPPM(t *testing.T) {
+	is := assert.New(t)
+
+	ppm := `P3
+# a comment on its own line
+2 2 # and after the header
+255
+255 0 0  0 255 0
+0 0 255  255 255 255
+`
+	c, err := ReadPPM(strings.NewReader(ppm))
+	is.NoError(err)
+	is.Equal(2, c.Width)
+	is.Equal(2, c.Height)
+	is.Equal(NewColor(1, 0, 0), c.PixelAt(0, 0))
+	is.Equal(NewColor(0, 1, 0), c.PixelAt(1, 0))
+	is.Equal(NewColor(0, 0, 1), c.PixelAt(0, 1))
+	is.Equal(NewColor(1, 1, 1), c.PixelAt(1, 1))
+
+	// the maximum value scales the colors
+	c, err = ReadPPM(strings.NewReader("P3 1 1 100 50 100 0"))
+	is.NoError(err)
+	is.Equal(NewColor(0.5, 1, 0), c.PixelAt(0, 0))
+}
+
+func TestReadPPMRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	c := NewCanvas(10, 3)
+	c.WriteAllPixels(NewColor(1, 0.8, 0.6))
+	c.WritePixel(4, 1, NewColor(0, 0.2, 1))
+
+	read, err := ReadPPM(strings.NewReader(c.ToPPM()))
+	is.NoError(err)
+	is.Equal(10, read.Width)
+	is.Equal(3, read.Height)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			is.Equal(c.PixelAt(x, y).toRGBString(), read.PixelAt(x, y).toRGBString())
+		}
+	}
+}
+
+func TestReadPPMInvalid(t *testing.T) {
+	is := assert.New(t)
+
+	for _, ppm := range []string{
+		"",
+		"P6 1 1 255 0 0 0",
+		"P3 x 1 255 0 0 0",
+		"P3 1 1 255 0 0",
+		"P3 1 1 255 0 0 red",
+	} {
+		_, err := ReadPPM(strings.NewReader(ppm))
+		is.Error(err, ppm)
+	}
+}
+
+func TestFromImage(t *testing.T) {
+	is := assert.New(t)
+
+	img := image.NewRGBA(image.Rect(5, 5, 7, 6))
+	img.Set(5, 5, color.RGBA{R: 255, G: 0, B: 0, A: 255})
+	img.Set(6, 5, color.RGBA{R: 0, G: 51, B: 255, A: 255})
+
+	c := FromImage(img)
+	is.Equal(2, c.Width)
+	is.Equal(1, c.Height)
+	is.True(c.PixelAt(0, 0).Equal(NewColor(1, 0, 0)))
+	is.True(c.PixelAt(1, 0).Equal(NewColor(0, 0.2, 1)))
+}
+
+func TestLoad(t *testing.T) {
+	is := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "canvas")
+	is.NoError(err)
+	defer os.RemoveAll(dir)
+
+	c := NewCanvas(1, 1)
+	c.WritePixel(0, 0, NewColor(0, 1, 0))
+	ppmPath := filepath.Join(dir, "texture.ppm")
+	is.NoError(ioutil.WriteFile(ppmPath, []byte(c.ToPPM()), 0644))
+
+	loaded, err := Load(ppmPath)
+	is.NoError(err)
+	is.Equal(NewColor(0, 1, 0), loaded.PixelAt(0, 0))
+
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{R: 0, G: 0, B: 255, A: 255})
+	pngPath := filepath.Join(dir, "texture.png")
+	f, err := os.Create(pngPath)
+	is.NoError(err)
+	is.NoError(png.Encode(f, img))
+	is.NoError(f.Close())
+
+	loaded, err = Load(pngPath)
+	is.NoError(err)
+	is.Equal(NewColor(0, 0, 1), loaded.PixelAt(0, 0))
+
+	_, err = Load(filepath.Join(dir, "missing.png"))
+	is.Error(err)
+	_, err = Load(ppmPath + "x")
+	is.Error(err)
+
+	is.NoError(ioutil.WriteFile(pngPath, []byte("not an image"), 0644))
+	_, err = Load(pngPath)
+	is.Error(err)
+}
@@ -1,34 +1,29 @@
 package main
 
 import (
-	"fmt"
 	"io/ioutil"
+	"os"
 
 	"github.com/muzfuz/raytrace/canvas"
-	"github.com/muzfuz/raytrace/matrix"
-
+	"github.com/muzfuz/raytrace/logging"
 	"github.com/muzfuz/raytrace/tuple"
 )
 
 func main() {
-	ident := matrix.Identity()
-	t := ident.MultiplyTuple(tuple.NewVector(2, 1, 0))
-	fmt.Println(t)
-
-	ident[0][0] = 5.0
-	t2 := ident.MultiplyTuple(tuple.NewVector(2, 1, 0))
-	fmt.Println(t2)
+	shootCannon()
 }
 
 func shootCannon() {
-	fmt.Println("setting up environment...")
+	log := logging.New(os.Stderr, false)
+	log.Info("setting up environment")
 	c := canvas.NewCanvas(900, 550)
+	c.Logger = log
 	red := canvas.NewColor(1, 0, 0)
 
 	e := newEnvironment()
-	p := newProjectile(tuple.NewPoint(0, 250, 0), tuple.NewVector(2, 1, 0).Normalize().Scale(8))
+	p := newProjectile(tuple.Pt(0, 250, 0), tuple.Vec(2, 1, 0).Normalize().Scale(8))
 
-	fmt.Println("running simulation...")
+	log.Info("running simulation")
 	for {
 		if p.Position.Y <= 0 {
 			break
@@ -38,10 +33,10 @@ func shootCannon() {
 		p = tick(e, p)
 	}
 
-	fmt.Println("writing to file...")
+	log.Info("writing to file", "path", "tmp/canvas.bmp")
 	err := ioutil.WriteFile("tmp/canvas.bmp", []byte(c.ToPPM()), 0664)
 	if err != nil {
-		fmt.Println(err)
+		log.Error("could not write canvas", "err", err)
 	}
 }
 
@@ -52,16 +47,16 @@ func tick(env environment, proj projectile) projectile {
 	return newProjectile(position, velocity)
 }
 
-func convertCoordinates(height int, position tuple.Tuple) (int, int) {
+func convertCoordinates(height int, position tuple.Point) (int, int) {
 	return int(position.X), height - int(position.Y)
 }
 
 type projectile struct {
-	Position tuple.Tuple // point
-	Velocity tuple.Tuple // vector
+	Position tuple.Point
+	Velocity tuple.Vector
 }
 
-func newProjectile(position, velocity tuple.Tuple) projectile {
+func newProjectile(position tuple.Point, velocity tuple.Vector) projectile {
 	return projectile{
 		Position: position,
 		Velocity: velocity,
@@ -69,13 +64,13 @@ func newProjectile(position, velocity tuple.Tuple) projectile {
 }
 
 type environment struct {
-	Gravity tuple.Tuple // vector
-	Wind    tuple.Tuple // vector
+	Gravity tuple.Vector
+	Wind    tuple.Vector
 }
 
 func newEnvironment() environment {
 	return environment{
-		Gravity: tuple.NewVector(0, -0.1, 0),
-		Wind:    tuple.NewVector(-0.01, 0, 0),
+		Gravity: tuple.Vec(0, -0.1, 0),
+		Wind:    tuple.Vec(-0.01, 0, 0),
 	}
 }
@@ -1,34 +1,37 @@
 package main
 
 import (
-	"fmt"
 	"io/ioutil"
 	"math"
+	"os"
 
 	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/logging"
 	"github.com/muzfuz/raytrace/matrix"
 	"github.com/muzfuz/raytrace/tuple"
 )
 
 func main() {
+	log := logging.New(os.Stderr, false)
 	dim := 400
 	mid := dim / 2
 	rad := float64(dim / 4)
 	c := canvas.NewCanvas(dim, dim)
+	c.Logger = log
 	white := canvas.NewColor(255, 255, 255)
 
-	twelve := tuple.NewPoint(0, 0, 1)
+	twelve := tuple.Pt(0, 0, 1)
 	for i := 0; i < 12; i++ {
 		rotation := matrix.RotationY(float64(i) * math.Pi / 6.0)
-		currTime := rotation.MultiplyTuple(twelve)
+		currTime := rotation.MultiplyPoint(twelve)
 		x, z := convertCoordinates(currTime.X*rad, currTime.Z*rad)
 		c.WritePixel(mid+x, mid+z, white)
 	}
 
-	fmt.Println("writing to file...")
+	log.Info("writing to file", "path", "tmp/canvas.bmp")
 	err := ioutil.WriteFile("tmp/canvas.bmp", []byte(c.ToPPM()), 0664)
 	if err != nil {
-		fmt.Println(err)
+		log.Error("could not write canvas", "err", err)
 	}
 }
 
@@ -0,0 +1,89 @@
+package environment
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/texture"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Environment is the light arriving from infinitely far away,
+// seen by rays that miss every object in the scene.
+type Environment interface {
+	ColorAt(direction tuple.Vector) canvas.Color
+}
+
+// Uniform is the same color in every direction
+type Uniform struct {
+	Color canvas.Color
+}
+
+// ColorAt returns the color
+func (e Uniform) ColorAt(direction tuple.Vector) canvas.Color {
+	return e.Color
+}
+
+// Sky is a procedural sky, blending from the Horizon color up to the
+// Zenith straight overhead, and from the Horizon down to the Ground.
+type Sky struct {
+	Zenith  canvas.Color
+	Horizon canvas.Color
+	Ground  canvas.Color
+}
+
+// ColorAt blends by the height of the direction above or below the horizon
+func (e Sky) ColorAt(direction tuple.Vector) canvas.Color {
+	y := direction.Normalize().Y
+	if y >= 0 {
+		return lerp(e.Horizon, e.Zenith, y)
+	}
+	return lerp(e.Horizon, e.Ground, -y)
+}
+
+// Equirectangular wraps a panorama around the scene, with longitude
+// running across the image and latitude up it, as most HDR
+// environment maps are stored. The center of the image is towards -z,
+// the default direction of the camera, and like the camera +x is to
+// the left, so a camera.Panorama reproduces the image.
+type Equirectangular struct {
+	Image texture.Image
+}
+
+// NewEquirectangular returns an environment that samples
+// the canvas with bilinear filtering.
+func NewEquirectangular(c canvas.Canvas) Equirectangular {
+	return Equirectangular{
+		Image: texture.NewImage(c),
+	}
+}
+
+// ColorAt samples the panorama in the direction
+func (e Equirectangular) ColorAt(direction tuple.Vector) canvas.Color {
+	d := direction.Normalize()
+	u := 0.5 + math.Atan2(-d.X, -d.Z)/(2*math.Pi)
+	v := 0.5 + math.Asin(math.Max(-1, math.Min(1, d.Y)))/math.Pi
+
+	// the image repeats around the horizon, but not over the poles
+	half := 0.5 / float64(e.Image.Canvas.Height)
+	v = math.Max(half, math.Min(1-half, v))
+	return e.Image.ColorAt(u, v)
+}
+
+// Skybox surrounds the scene with a cube of six images.
+// Each face is an image as seen by the camera from inside the cube.
+type Skybox struct {
+	Cube texture.Cube
+}
+
+// ColorAt samples the face of the cube in the direction
+func (e Skybox) ColorAt(direction tuple.Vector) canvas.Color {
+	a := direction.Abs()
+	d := direction.Divide(math.Max(a.X, math.Max(a.Y, a.Z)))
+	face, u, v := texture.CubeMap(tuple.Pt(d.X, d.Y, d.Z))
+	return e.Cube.Face(face).ColorAt(u, v)
+}
+
+func lerp(a, b canvas.Color, t float64) canvas.Color {
+	return a.Add(b.Subtract(a).Scale(t))
+}
@@ -0,0 +1,147 @@
+package environment
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/camera"
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/texture"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// solid is a texture of a single color
+type solid canvas.Color
+
+func (s solid) ColorAt(u, v float64) canvas.Color {
+	return canvas.Color(s)
+}
+
+// uv is a texture that returns its coordinates as a color
+type uv struct{}
+
+func (uv) ColorAt(u, v float64) canvas.Color {
+	return canvas.NewColor(u, v, 0)
+}
+
+func TestUniform(t *testing.T) {
+	is := assert.New(t)
+
+	var e Environment = Uniform{Color: canvas.NewColor(0.1, 0.2, 0.3)}
+	is.Equal(canvas.NewColor(0.1, 0.2, 0.3), e.ColorAt(tuple.Vec(0, 1, 0)))
+	is.Equal(canvas.NewColor(0.1, 0.2, 0.3), e.ColorAt(tuple.Vec(1, -1, 0)))
+}
+
+func TestSky(t *testing.T) {
+	is := assert.New(t)
+
+	e := Sky{
+		Zenith:  canvas.NewColor(0, 0, 1),
+		Horizon: canvas.NewColor(1, 1, 1),
+		Ground:  canvas.NewColor(0, 0, 0),
+	}
+	is.True(e.ColorAt(tuple.Vec(0, 2, 0)).Equal(canvas.NewColor(0, 0, 1)))
+	is.True(e.ColorAt(tuple.Vec(1, 0, 0)).Equal(canvas.NewColor(1, 1, 1)))
+	is.True(e.ColorAt(tuple.Vec(0, -1, 0)).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(e.ColorAt(tuple.Vec(math.Sqrt(3), 1, 0)).Equal(canvas.NewColor(0.5, 0.5, 1)))
+	is.True(e.ColorAt(tuple.Vec(0, -1, math.Sqrt(3))).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+}
+
+func TestEquirectangular(t *testing.T) {
+	is := assert.New(t)
+
+	// four columns around the horizon, above and below it
+	c := canvas.NewCanvas(4, 2)
+	for x := 0; x < 4; x++ {
+		c.WritePixel(x, 0, canvas.NewColor(float64(x)/4, 1, 0))
+		c.WritePixel(x, 1, canvas.NewColor(float64(x)/4, 0, 0))
+	}
+	e := NewEquirectangular(c)
+	is.Equal(texture.Bilinear, e.Image.Filter)
+
+	e.Image.Filter = texture.Nearest
+	tests := []struct {
+		direction tuple.Vector
+		color     canvas.Color
+	}{
+		// straight ahead is the center of the image
+		{tuple.Vec(0, 0.1, -1), canvas.NewColor(0.5, 1, 0)},
+		{tuple.Vec(0, -0.1, -1), canvas.NewColor(0.5, 0, 0)},
+		// like the camera, +x is on the left
+		{tuple.Vec(1, 0.1, 0), canvas.NewColor(0.25, 1, 0)},
+		{tuple.Vec(-1, 0.1, 0), canvas.NewColor(0.75, 1, 0)},
+		{tuple.Vec(0.1, 0.1, 1), canvas.NewColor(0, 1, 0)},
+	}
+	for _, tt := range tests {
+		is.True(e.ColorAt(tt.direction).Equal(tt.color), "%v", tt.direction)
+	}
+
+	// the poles use the top and bottom rows
+	is.Equal(1.0, e.ColorAt(tuple.Vec(0, 1, 0)).G())
+	is.Equal(0.0, e.ColorAt(tuple.Vec(0, -1, 0)).G())
+
+	// filtering wraps around the horizon, but does not blend over the poles
+	e.Image.Filter = texture.Bilinear
+	is.True(e.ColorAt(tuple.Vec(0, 2, 1)).Equal(canvas.NewColor(0.375, 1, 0)))
+	is.True(e.ColorAt(tuple.Vec(0, 1, 0)).G() == 1)
+	is.True(e.ColorAt(tuple.Vec(0, -1, 0)).G() == 0)
+}
+
+func TestSkybox(t *testing.T) {
+	is := assert.New(t)
+
+	e := Skybox{Cube: texture.Cube{
+		Left:  solid(canvas.NewColor(1, 1, 0)),
+		Front: solid(canvas.NewColor(0, 1, 1)),
+		Right: solid(canvas.NewColor(1, 0, 0)),
+		Back:  solid(canvas.NewColor(0, 1, 0)),
+		Up:    solid(canvas.NewColor(1, 0.5, 0)),
+		Down:  solid(canvas.NewColor(1, 1, 1)),
+	}}
+	is.Equal(canvas.NewColor(1, 1, 0), e.ColorAt(tuple.Vec(-5, 1, 2)))
+	is.Equal(canvas.NewColor(0, 1, 1), e.ColorAt(tuple.Vec(0, 0, 0.1)))
+	is.Equal(canvas.NewColor(1, 0, 0), e.ColorAt(tuple.Vec(3, -2, 0)))
+	is.Equal(canvas.NewColor(0, 1, 0), e.ColorAt(tuple.Vec(0, 0, -1)))
+	is.Equal(canvas.NewColor(1, 0.5, 0), e.ColorAt(tuple.Vec(0, 1, 0)))
+	is.Equal(canvas.NewColor(1, 1, 1), e.ColorAt(tuple.Vec(0.2, -1, 0.3)))
+
+	// the camera turned around to look at the front face sees -x on the left
+	e.Cube.Front = uv{}
+	is.True(e.ColorAt(tuple.Vec(0.5, -0.5, 1)).Equal(canvas.NewColor(0.75, 0.25, 0)))
+	is.True(e.ColorAt(tuple.Vec(-1, 1, 2)).Equal(canvas.NewColor(0.25, 0.75, 0)))
+
+	// and looking at the back face, +x is on the left
+	e.Cube.Back = uv{}
+	is.True(e.ColorAt(tuple.Vec(0.5, -0.5, -1)).Equal(canvas.NewColor(0.25, 0.25, 0)))
+}
+
+func TestPanoramaReproducesEquirectangular(t *testing.T) {
+	is := assert.New(t)
+
+	img := canvas.NewCanvas(32, 16)
+	rnd := rand.New(rand.NewSource(1))
+	for y := 0; y < img.Height; y++ {
+		for x := 0; x < img.Width; x++ {
+			img.WritePixel(x, y, canvas.NewColor(rnd.Float64(), rnd.Float64(), rnd.Float64()))
+		}
+	}
+	e := NewEquirectangular(img)
+	e.Image.Filter = texture.Nearest
+
+	c := camera.New(img.Width, img.Height, math.Pi/2)
+	c.Projection = camera.Panorama{}
+	render, err := c.Render(context.Background(), func(r ray.Ray) canvas.Color {
+		return e.ColorAt(r.Direction)
+	}, nil)
+	is.NoError(err)
+	for y := 0; y < img.Height; y++ {
+		for x := 0; x < img.Width; x++ {
+			is.True(render.PixelAt(x, y).Equal(img.PixelAt(x, y)), "%d, %d", x, y)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package environment
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Sampler is an environment that can also be used as a light,
+// by picking the directions that contribute the most light more often.
+type Sampler interface {
+	Environment
+	// Sample turns two uniform random numbers into a direction, and
+	// returns it with its probability density per unit of solid angle.
+	Sample(u1, u2 float64) (tuple.Vector, float64)
+	// PDF returns the probability density of Sample picking the direction
+	PDF(direction tuple.Vector) float64
+}
+
+// HDRI is an equirectangular environment that importance samples
+// its pixels by their luminance, so that the sun or the softboxes of
+// a studio HDRI light the scene without needing a lot of samples.
+type HDRI struct {
+	Equirectangular
+	// rows is the cumulative distribution of picking each row, and cols
+	// of picking each pixel within its row. Both start at 0.0 and end at 1.0.
+	rows []float64
+	cols [][]float64
+}
+
+// NewHDRI builds the luminance distribution of the canvas.
+// An entirely black canvas is sampled uniformly, while an empty
+// canvas has nothing to sample and returns an error.
+func NewHDRI(c canvas.Canvas) (HDRI, error) {
+	w, h := c.Width, c.Height
+	if w < 1 || h < 1 {
+		return HDRI{}, errors.New("cannot sample an empty canvas")
+	}
+	weights := make([][]float64, h)
+	total := 0.0
+	for y := range weights {
+		// rows near the poles cover less of the sphere
+		sin := math.Sin(math.Pi * (float64(y) + 0.5) / float64(h))
+		weights[y] = make([]float64, w)
+		for x := range weights[y] {
+			weights[y][x] = c.PixelAt(x, y).Luminance() * sin
+			total += weights[y][x]
+		}
+	}
+	if total <= 0 {
+		for y := range weights {
+			sin := math.Sin(math.Pi * (float64(y) + 0.5) / float64(h))
+			for x := range weights[y] {
+				weights[y][x] = sin
+			}
+		}
+	}
+
+	e := HDRI{
+		Equirectangular: NewEquirectangular(c),
+		cols:            make([][]float64, h),
+	}
+	rowWeights := make([]float64, h)
+	for y := range weights {
+		e.cols[y] = cdf(weights[y])
+		for _, weight := range weights[y] {
+			rowWeights[y] += weight
+		}
+	}
+	e.rows = cdf(rowWeights)
+	return e, nil
+}
+
+// Sample picks a pixel in proportion to its weight, and a random
+// direction within it.
+func (e HDRI) Sample(u1, u2 float64) (tuple.Vector, float64) {
+	y, dy := pick(e.rows, u1)
+	x, dx := pick(e.cols[y], u2)
+	u := (float64(x) + dx) / float64(e.width())
+	v := 1 - (float64(y)+dy)/float64(e.height())
+
+	phi := (u - 0.5) * 2 * math.Pi
+	lat := (v - 0.5) * math.Pi
+	sinPhi, cosPhi := math.Sincos(phi)
+	sinLat, cosLat := math.Sincos(lat)
+	d := tuple.Vec(-cosLat*sinPhi, sinLat, -cosLat*cosPhi)
+	return d, e.density(x, y, cosLat)
+}
+
+// PDF returns the probability density of Sample picking the direction
+func (e HDRI) PDF(direction tuple.Vector) float64 {
+	d := direction.Normalize()
+	u := 0.5 + math.Atan2(-d.X, -d.Z)/(2*math.Pi)
+	lat := math.Asin(math.Max(-1, math.Min(1, d.Y)))
+	v := 0.5 + lat/math.Pi
+
+	w, h := e.width(), e.height()
+	x := int(math.Floor(u*float64(w))) % w
+	if x < 0 {
+		x += w
+	}
+	y := int(math.Floor((1 - v) * float64(h)))
+	if y < 0 {
+		y = 0
+	}
+	if y >= h {
+		y = h - 1
+	}
+	return e.density(x, y, math.Cos(lat))
+}
+
+// density converts the probability of picking a pixel into a
+// probability per unit of solid angle. Pixels are stretched out over
+// the image by 1 / cos(latitude) compared to the sphere.
+func (e HDRI) density(x, y int, cosLat float64) float64 {
+	if cosLat <= 0 {
+		return 0
+	}
+	p := (e.rows[y+1] - e.rows[y]) * (e.cols[y][x+1] - e.cols[y][x])
+	pixels := float64(e.width() * e.height())
+	return p * pixels / (2 * math.Pi * math.Pi * cosLat)
+}
+
+func (e HDRI) width() int {
+	return e.Image.Canvas.Width
+}
+
+func (e HDRI) height() int {
+	return e.Image.Canvas.Height
+}
+
+// cdf returns the normalized running total of the weights,
+// one longer than the weights and starting at 0.0.
+// If every weight is zero, every one is equally likely.
+func cdf(weights []float64) []float64 {
+	c := make([]float64, len(weights)+1)
+	for i, w := range weights {
+		c[i+1] = c[i] + w
+	}
+	total := c[len(weights)]
+	for i := range c {
+		if total > 0 {
+			c[i] /= total
+		} else {
+			c[i] = float64(i) / float64(len(weights))
+		}
+	}
+	// guard against rounding leaving the total just short of 1.0
+	c[len(weights)] = 1
+	return c
+}
+
+// pick returns the index of the bucket of the cdf that u falls into,
+// and how far into the bucket it is. Empty buckets are never picked.
+func pick(c []float64, u float64) (int, float64) {
+	n := len(c) - 1
+	i := sort.Search(len(c), func(i int) bool { return c[i] > u }) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= n {
+		i = n - 1
+	}
+	width := c[i+1] - c[i]
+	if width <= 0 {
+		return i, 0.5
+	}
+	return i, (u - c[i]) / width
+}
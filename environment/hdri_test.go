@@ -0,0 +1,152 @@
+package environment
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/texture"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// studio returns a dim canvas with one bright softbox
+func studio() canvas.Canvas {
+	c := canvas.NewCanvas(16, 8)
+	c.WriteAllPixels(canvas.NewColor(0.01, 0.01, 0.01))
+	c.WritePixel(5, 2, canvas.NewColor(100, 100, 100))
+	return c
+}
+
+// softbox is the direction of the center of the softbox, worked out
+// the way a camera.Panorama would see it
+func softbox() tuple.Vector {
+	lon := 2 * math.Pi * (0.5 - 5.5/16)
+	lat := math.Pi * (0.5 - 2.5/8)
+	return tuple.Vec(math.Sin(lon)*math.Cos(lat), math.Sin(lat), -math.Cos(lon)*math.Cos(lat))
+}
+
+func TestCDF(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal([]float64{0, 0.25, 0.25, 1}, cdf([]float64{1, 0, 3}))
+	is.Equal([]float64{0, 0.5, 1}, cdf([]float64{0, 0}))
+}
+
+func TestPick(t *testing.T) {
+	is := assert.New(t)
+
+	c := []float64{0, 0.25, 0.25, 1}
+	i, d := pick(c, 0)
+	is.Equal(0, i)
+	is.Equal(0.0, d)
+	i, d = pick(c, 0.125)
+	is.Equal(0, i)
+	is.Equal(0.5, d)
+
+	// the empty bucket is skipped
+	i, d = pick(c, 0.25)
+	is.Equal(2, i)
+	is.Equal(0.0, d)
+	i, d = pick(c, 0.625)
+	is.Equal(2, i)
+	is.Equal(0.5, d)
+}
+
+func TestHDRIIsAnEnvironment(t *testing.T) {
+	is := assert.New(t)
+
+	c := studio()
+	hdri, err := NewHDRI(c)
+	is.NoError(err)
+	var e Sampler = hdri
+	eq := NewEquirectangular(c)
+	for _, d := range []tuple.Vector{tuple.Vec(0, 0, -1), tuple.Vec(1, 2, 3), tuple.Vec(0, -1, 0.1)} {
+		is.Equal(eq.ColorAt(d), e.ColorAt(d))
+	}
+	is.True(e.ColorAt(softbox()).Equal(canvas.NewColor(100, 100, 100)))
+}
+
+func TestHDRIUniform(t *testing.T) {
+	is := assert.New(t)
+
+	c := canvas.NewCanvas(64, 32)
+	c.WriteAllPixels(canvas.NewColor(1, 1, 1))
+	e, err := NewHDRI(c)
+	is.NoError(err)
+
+	// a uniform environment is sampled evenly over the sphere,
+	// apart from the error of treating each row as a single latitude
+	for _, d := range []tuple.Vector{tuple.Vec(0, 0, -1), tuple.Vec(1, 0, 0), tuple.Vec(1, 1, 1)} {
+		is.InEpsilon(1/(4*math.Pi), e.PDF(d), 0.03)
+	}
+
+	// as is a black one, rather than not at all
+	black, err := NewHDRI(canvas.NewCanvas(64, 32))
+	is.NoError(err)
+	is.InEpsilon(1/(4*math.Pi), black.PDF(tuple.Vec(0, 0, -1)), 0.01)
+	d, pdf := black.Sample(0.3, 0.7)
+	is.True(float.Equal(1, d.Magnitude()))
+	is.InEpsilon(1/(4*math.Pi), pdf, 0.03)
+}
+
+func TestHDRISample(t *testing.T) {
+	is := assert.New(t)
+
+	e, err := NewHDRI(studio())
+	is.NoError(err)
+	nearest := e.Image
+	nearest.Filter = texture.Nearest
+	bright := Equirectangular{Image: nearest}
+	rnd := rand.New(rand.NewSource(1))
+
+	const n = 1000
+	hits, near := 0, 0
+	for i := 0; i < n; i++ {
+		d, pdf := e.Sample(rnd.Float64(), rnd.Float64())
+		is.True(float.Equal(1, d.Magnitude()))
+		// Sample and PDF agree on the density
+		is.InEpsilon(pdf, e.PDF(d), 1e-6)
+		if bright.ColorAt(d).R() == 100 {
+			hits++
+		}
+		// within half a pixel diagonal of the center of the softbox
+		if d.Dot(softbox()) > math.Cos(0.26) {
+			near++
+		}
+	}
+	// nearly all of the light comes from the softbox
+	is.True(hits > n*95/100, "%d of %d samples hit the softbox", hits, n)
+	is.True(near > n*95/100, "%d of %d samples were near the softbox", near, n)
+}
+
+func TestHDRIPDFIntegratesToOne(t *testing.T) {
+	is := assert.New(t)
+
+	e, err := NewHDRI(studio())
+	is.NoError(err)
+	rnd := rand.New(rand.NewSource(1))
+
+	// average the density over uniformly random directions
+	const n = 200000
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		z := 2*rnd.Float64() - 1
+		r := math.Sqrt(1 - z*z)
+		sin, cos := math.Sincos(2 * math.Pi * rnd.Float64())
+		sum += e.PDF(tuple.Vec(r*cos, r*sin, z))
+	}
+	is.InDelta(1.0, sum/n*4*math.Pi, 0.05)
+}
+
+func TestHDRIEmpty(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := NewHDRI(canvas.NewCanvas(0, 0))
+	is.Error(err)
+	_, err = NewHDRI(canvas.NewCanvas(4, 0))
+	is.Error(err)
+}
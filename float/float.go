@@ -4,13 +4,82 @@ import (
 	"math"
 )
 
-const epsilon = 0.00001
+// Comparator decides whether two floats can be considered equal
+type Comparator interface {
+	Equal(a, b float64) bool
+}
+
+// Default is the Comparator used by Equal, and so by the Equal
+// methods of tuples, matrices and colors. Replace it to change the
+// tolerance for a whole program, before any rendering starts.
+var Default Comparator = Tolerance{Abs: 0.00001}
 
 // Equal returns wether or not two floats
-// can be considered equal
+// can be considered equal, according to the Default comparator
 func Equal(a, b float64) bool {
-	if math.Abs(a-b) < epsilon {
+	return Default.Equal(a, b)
+}
+
+// Tolerance compares floats using an absolute and a relative tolerance.
+// Abs handles values near zero, where any relative tolerance is too
+// strict, and Rel handles large values, where a fixed absolute
+// tolerance is smaller than the gap between neighbouring floats.
+type Tolerance struct {
+	Abs float64
+	Rel float64
+}
+
+// Equal returns true if a and b differ by less than Abs, or by no
+// more than Rel times the larger of their magnitudes.
+// NaN is never equal to anything.
+func (t Tolerance) Equal(a, b float64) bool {
+	if a == b {
 		return true
 	}
-	return false
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	diff := math.Abs(a - b)
+	if diff < t.Abs {
+		return true
+	}
+	return diff <= t.Rel*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// ULPs compares floats by how many representable floats lie between
+// them, units in the last place, which scales with their magnitude.
+// It is not useful near zero, where the floats are very dense,
+// so combine it with an absolute Tolerance when values may be small.
+type ULPs uint64
+
+// Equal returns true if a and b are at most u floats apart.
+// NaN is never equal to anything.
+func (u ULPs) Equal(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	return ULPDistance(a, b) <= uint64(u)
+}
+
+// ULPDistance returns how many representable floats lie between a and b.
+// 0 and -0 are the same float, and NaN is as far from everything as possible.
+func ULPDistance(a, b float64) uint64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+	ia, ib := ordered(a), ordered(b)
+	if ia > ib {
+		return uint64(ia) - uint64(ib)
+	}
+	return uint64(ib) - uint64(ia)
+}
+
+// ordered maps the bits of a float onto an integer that
+// sorts the same way as the float itself.
+func ordered(f float64) int64 {
+	i := int64(math.Float64bits(f))
+	if i < 0 {
+		return math.MinInt64 - i
+	}
+	return i
 }
@@ -0,0 +1,87 @@
+package float
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(Equal(1, 1.000001))
+	is.False(Equal(1, 1.0001))
+	is.True(Equal(math.Inf(1), math.Inf(1)))
+	is.False(Equal(math.NaN(), math.NaN()))
+}
+
+func TestDefault(t *testing.T) {
+	is := assert.New(t)
+
+	old := Default
+	defer func() { Default = old }()
+
+	Default = Tolerance{Abs: 0.01}
+	is.True(Equal(1, 1.001))
+	is.False(Equal(1, 1.1))
+}
+
+func TestTolerance(t *testing.T) {
+	is := assert.New(t)
+
+	// an absolute tolerance fails for large values
+	abs := Tolerance{Abs: 0.00001}
+	is.False(abs.Equal(1e9, 1e9+0.1))
+	is.True(abs.Equal(1e-9, 2e-9))
+
+	// a relative tolerance fails for small values
+	rel := Tolerance{Rel: 1e-9}
+	is.True(rel.Equal(1e9, 1e9+0.1))
+	is.False(rel.Equal(1e-9, 2e-9))
+	is.False(rel.Equal(0, 1e-300))
+
+	// together they handle both
+	both := Tolerance{Abs: 0.00001, Rel: 1e-9}
+	is.True(both.Equal(1e9, 1e9+0.1))
+	is.True(both.Equal(1e-9, 2e-9))
+	is.False(both.Equal(1e9, 1e9+10))
+
+	is.True(both.Equal(math.Inf(-1), math.Inf(-1)))
+	is.False(both.Equal(math.Inf(1), math.Inf(-1)))
+	is.False(both.Equal(math.NaN(), 0))
+}
+
+func TestULPDistance(t *testing.T) {
+	is := assert.New(t)
+
+	one := 1.0
+	next := math.Nextafter(one, 2)
+	is.Equal(uint64(0), ULPDistance(one, one))
+	is.Equal(uint64(1), ULPDistance(one, next))
+	is.Equal(uint64(1), ULPDistance(next, one))
+	is.Equal(uint64(2), ULPDistance(math.Nextafter(one, 0), next))
+
+	// the distance is counted straight through zero
+	is.Equal(uint64(0), ULPDistance(0, math.Copysign(0, -1)))
+	tiny := math.SmallestNonzeroFloat64
+	is.Equal(uint64(2), ULPDistance(-tiny, tiny))
+
+	is.Equal(uint64(math.MaxUint64), ULPDistance(math.NaN(), 1))
+}
+
+func TestULPs(t *testing.T) {
+	is := assert.New(t)
+
+	// constants are exact in Go, so add at runtime to get the rounding error
+	a, b := 0.1, 0.2
+	is.True(ULPs(4).Equal(a+b, 0.3))
+	is.False(ULPs(0).Equal(a+b, 0.3))
+	is.True(ULPs(0).Equal(0, math.Copysign(0, -1)))
+	is.False(ULPs(4).Equal(math.NaN(), math.NaN()))
+
+	// the tolerance grows with the magnitude of the values
+	big := 1e15
+	is.True(ULPs(1).Equal(big, math.Nextafter(big, math.Inf(1))))
+	is.False(ULPs(1).Equal(big, big+1))
+}
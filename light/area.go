@@ -0,0 +1,186 @@
+package light
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// AreaLight is a rectangular light, defined by a corner
+// and the two vectors that make up its edges.
+// It is split into USteps x VSteps cells, and every cell is
+// sampled once, which gives shadows a soft penumbra.
+// When Jitter is nil cells are sampled at their center,
+// otherwise at a random point within the cell.
+type AreaLight struct {
+	Corner    tuple.Point
+	UVec      tuple.Vector
+	USteps    int
+	VVec      tuple.Vector
+	VSteps    int
+	Jitter    *rand.Rand
+	intensity canvas.Color
+}
+
+// NewAreaLight constructs an AreaLight. The full edge vectors are passed in,
+// and are divided by the number of steps to find the size of a cell.
+func NewAreaLight(corner tuple.Point, fullUVec tuple.Vector, usteps int, fullVVec tuple.Vector, vsteps int, intensity canvas.Color) (AreaLight, error) {
+	if usteps < 1 || vsteps < 1 {
+		return AreaLight{}, errors.New("area light needs at least one step in each direction")
+	}
+	return AreaLight{
+		Corner:    corner,
+		UVec:      fullUVec.Divide(float64(usteps)),
+		USteps:    usteps,
+		VVec:      fullVVec.Divide(float64(vsteps)),
+		VSteps:    vsteps,
+		intensity: intensity,
+	}, nil
+}
+
+// Position returns the center of the light
+func (l AreaLight) Position() tuple.Point {
+	return l.Corner.
+		Add(l.UVec.Scale(float64(l.USteps) / 2)).
+		Add(l.VVec.Scale(float64(l.VSteps) / 2))
+}
+
+// PointOnLight returns a point within the given cell of the light
+func (l AreaLight) PointOnLight(u, v int) tuple.Point {
+	return l.Corner.
+		Add(l.UVec.Scale(float64(u) + l.jitter())).
+		Add(l.VVec.Scale(float64(v) + l.jitter()))
+}
+
+// Points returns one point per cell
+func (l AreaLight) Points() []tuple.Point {
+	points := make([]tuple.Point, 0, l.USteps*l.VSteps)
+	for v := 0; v < l.VSteps; v++ {
+		for u := 0; u < l.USteps; u++ {
+			points = append(points, l.PointOnLight(u, v))
+		}
+	}
+	return points
+}
+
+// Samples returns the light arriving from each of the Points
+func (l AreaLight) Samples(point tuple.Point) []Sample {
+	return samplePoints(point, l.Points(), l.intensity)
+}
+
+// Area returns the area of the whole light
+func (l AreaLight) Area() float64 {
+	return l.UVec.Cross(l.VVec).Magnitude() * float64(l.USteps*l.VSteps)
+}
+
+// Normal returns the unit vector perpendicular to the light
+func (l AreaLight) Normal() tuple.Vector {
+	return l.UVec.Cross(l.VVec).Normalize()
+}
+
+// Intensity returns the color of the light
+func (l AreaLight) Intensity() canvas.Color {
+	return l.intensity
+}
+
+func (l AreaLight) jitter() float64 {
+	if l.Jitter == nil {
+		return 0.5
+	}
+	return l.Jitter.Float64()
+}
+
+// DiscLight is a round light, facing along its normal.
+// It is split into Rings x Sectors cells of equal area, and every cell
+// is sampled once. Jitter works the same as for an AreaLight.
+type DiscLight struct {
+	Center    tuple.Point
+	Radius    float64
+	Rings     int
+	Sectors   int
+	Jitter    *rand.Rand
+	u         tuple.Vector
+	v         tuple.Vector
+	intensity canvas.Color
+}
+
+// NewDiscLight constructs a DiscLight
+func NewDiscLight(center tuple.Point, normal tuple.Vector, radius float64, rings, sectors int, intensity canvas.Color) (DiscLight, error) {
+	if normal.Magnitude() == 0 {
+		return DiscLight{}, errors.New("normal must not be the zero vector")
+	}
+	if rings < 1 || sectors < 1 {
+		return DiscLight{}, errors.New("disc light needs at least one ring and one sector")
+	}
+	u, v := tuple.OrthonormalBasis(normal.Normalize())
+	return DiscLight{
+		Center:    center,
+		Radius:    radius,
+		Rings:     rings,
+		Sectors:   sectors,
+		u:         u,
+		v:         v,
+		intensity: intensity,
+	}, nil
+}
+
+// PointOnLight returns a point within the given ring and sector of the disc.
+// Rings are spaced by area rather than by radius,
+// so that every cell covers the same amount of the disc.
+func (l DiscLight) PointOnLight(ring, sector int) tuple.Point {
+	r := l.Radius * math.Sqrt((float64(ring)+l.jitter())/float64(l.Rings))
+	theta := 2 * math.Pi * (float64(sector) + l.jitter()) / float64(l.Sectors)
+	return l.Center.
+		Add(l.u.Scale(r * math.Cos(theta))).
+		Add(l.v.Scale(r * math.Sin(theta)))
+}
+
+// Points returns one point per cell
+func (l DiscLight) Points() []tuple.Point {
+	points := make([]tuple.Point, 0, l.Rings*l.Sectors)
+	for ring := 0; ring < l.Rings; ring++ {
+		for sector := 0; sector < l.Sectors; sector++ {
+			points = append(points, l.PointOnLight(ring, sector))
+		}
+	}
+	return points
+}
+
+// Samples returns the light arriving from each of the Points
+func (l DiscLight) Samples(point tuple.Point) []Sample {
+	return samplePoints(point, l.Points(), l.intensity)
+}
+
+// Area returns the area of the whole light
+func (l DiscLight) Area() float64 {
+	return math.Pi * l.Radius * l.Radius
+}
+
+// Normal returns the unit vector the light faces along
+func (l DiscLight) Normal() tuple.Vector {
+	return l.u.Cross(l.v)
+}
+
+// Intensity returns the color of the light
+func (l DiscLight) Intensity() canvas.Color {
+	return l.intensity
+}
+
+func (l DiscLight) jitter() float64 {
+	if l.Jitter == nil {
+		return 0.5
+	}
+	return l.Jitter.Float64()
+}
+
+// samplePoints returns the light arriving at point from every position
+func samplePoints(point tuple.Point, positions []tuple.Point, intensity canvas.Color) []Sample {
+	samples := make([]Sample, len(positions))
+	for i, p := range positions {
+		samples[i] = towards(point, p, intensity)
+	}
+	return samples
+}
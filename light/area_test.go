@@ -0,0 +1,128 @@
+package light
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAreaLight(t *testing.T) {
+	is := assert.New(t)
+
+	corner := tuple.Pt(0, 0, 0)
+	v1 := tuple.Vec(2, 0, 0)
+	v2 := tuple.Vec(0, 0, 1)
+
+	l, err := NewAreaLight(corner, v1, 4, v2, 2, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+	is.Equal(corner, l.Corner)
+	is.Equal(tuple.Vec(0.5, 0, 0), l.UVec)
+	is.Equal(4, l.USteps)
+	is.Equal(tuple.Vec(0, 0, 0.5), l.VVec)
+	is.Equal(2, l.VSteps)
+	is.Len(l.Points(), 8)
+	is.Equal(tuple.Pt(1, 0, 0.5), l.Position())
+
+	_, err = NewAreaLight(corner, v1, 0, v2, 2, canvas.NewColor(1, 1, 1))
+	is.Error(err)
+}
+
+func TestAreaLightPointOnLight(t *testing.T) {
+	is := assert.New(t)
+
+	l, _ := NewAreaLight(tuple.Pt(0, 0, 0), tuple.Vec(2, 0, 0), 4, tuple.Vec(0, 0, 1), 2, canvas.NewColor(1, 1, 1))
+
+	is.True(l.PointOnLight(0, 0).Equal(tuple.Pt(0.25, 0, 0.25)))
+	is.True(l.PointOnLight(1, 0).Equal(tuple.Pt(0.75, 0, 0.25)))
+	is.True(l.PointOnLight(0, 1).Equal(tuple.Pt(0.25, 0, 0.75)))
+	is.True(l.PointOnLight(2, 0).Equal(tuple.Pt(1.25, 0, 0.25)))
+	is.True(l.PointOnLight(3, 1).Equal(tuple.Pt(1.75, 0, 0.75)))
+}
+
+func TestAreaLightJitter(t *testing.T) {
+	is := assert.New(t)
+
+	l, _ := NewAreaLight(tuple.Pt(0, 0, 0), tuple.Vec(2, 0, 0), 4, tuple.Vec(0, 0, 1), 2, canvas.NewColor(1, 1, 1))
+	l.Jitter = rand.New(rand.NewSource(1))
+
+	// every jittered sample stays within its own cell
+	for v := 0; v < l.VSteps; v++ {
+		for u := 0; u < l.USteps; u++ {
+			p := l.PointOnLight(u, v)
+			is.True(p.X >= float64(u)*0.5 && p.X < float64(u+1)*0.5)
+			is.True(p.Z >= float64(v)*0.5 && p.Z < float64(v+1)*0.5)
+			is.Equal(0.0, p.Y)
+		}
+	}
+}
+
+func TestNewDiscLight(t *testing.T) {
+	is := assert.New(t)
+
+	center := tuple.Pt(0, 10, 0)
+	l, err := NewDiscLight(center, tuple.Vec(0, -1, 0), 2, 2, 4, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+	is.Len(l.Points(), 8)
+
+	for _, s := range l.Points() {
+		// every sample lies within the disc, on the plane facing down
+		is.True(float.Equal(10, s.Y))
+		is.True(s.Subtract(center).Magnitude() <= 2)
+	}
+
+	_, err = NewDiscLight(center, tuple.Vec(0, 0, 0), 2, 2, 4, canvas.NewColor(1, 1, 1))
+	is.Error(err)
+	_, err = NewDiscLight(center, tuple.Vec(0, -1, 0), 2, 0, 4, canvas.NewColor(1, 1, 1))
+	is.Error(err)
+}
+
+func TestDiscLightPointOnLight(t *testing.T) {
+	is := assert.New(t)
+
+	l, _ := NewDiscLight(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, 1), 1, 2, 4, canvas.NewColor(1, 1, 1))
+
+	// rings split the disc into equal areas, so the unjittered inner ring
+	// sits at the radius enclosing a quarter of the disc
+	p := l.PointOnLight(0, 0)
+	is.True(float.Equal(0, p.Z))
+	is.True(float.Equal(math.Sqrt(0.25), p.Subtract(tuple.Pt(0, 0, 0)).Magnitude()))
+
+	p = l.PointOnLight(1, 0)
+	is.True(float.Equal(math.Sqrt(0.75), p.Subtract(tuple.Pt(0, 0, 0)).Magnitude()))
+}
+
+func TestLightArea(t *testing.T) {
+	is := assert.New(t)
+
+	area, _ := NewAreaLight(tuple.Pt(0, 0, 0), tuple.Vec(2, 0, 0), 4, tuple.Vec(0, 0, 3), 2, canvas.NewColor(1, 1, 1))
+	is.True(float.Equal(6, area.Area()))
+	is.True(area.Normal().Equal(tuple.Vec(0, -1, 0)))
+
+	disc, _ := NewDiscLight(tuple.Pt(0, 10, 0), tuple.Vec(0, -2, 0), 2, 2, 4, canvas.NewColor(1, 1, 1))
+	is.True(float.Equal(4*math.Pi, disc.Area()))
+	is.True(disc.Normal().Equal(tuple.Vec(0, -1, 0)))
+	disc, _ = NewDiscLight(tuple.Pt(0, 10, 0), tuple.Vec(1, 2, 3), 2, 2, 4, canvas.NewColor(1, 1, 1))
+	is.True(disc.Normal().Equal(tuple.Vec(1, 2, 3).Normalize()))
+}
+
+func TestAreaLightSamples(t *testing.T) {
+	is := assert.New(t)
+
+	l, _ := NewAreaLight(tuple.Pt(-1, 5, -1), tuple.Vec(2, 0, 0), 2, tuple.Vec(0, 0, 2), 2, canvas.NewColor(1, 1, 1))
+	point := tuple.Pt(0, 0, 0)
+	samples := l.Samples(point)
+
+	is.Len(samples, 4)
+	for i, s := range samples {
+		p := l.Points()[i]
+		is.True(float.Equal(p.Subtract(point).Magnitude(), s.Distance))
+		is.True(point.Add(s.Direction.Scale(s.Distance)).Equal(p))
+		is.Equal(canvas.NewColor(1, 1, 1), s.Intensity)
+	}
+}
@@ -0,0 +1,185 @@
+package light
+
+import (
+	"errors"
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Light is a source of light in the scene.
+// Samples returns the light arriving at a point, split into one Sample
+// per position on the light that shading and shadows should be calculated
+// against. Intensity returns the color of the light itself, which
+// is what contributes to ambient lighting.
+type Light interface {
+	Samples(point tuple.Point) []Sample
+	Intensity() canvas.Color
+}
+
+// Sample is light arriving at a point from a single direction.
+// Direction is the unit vector from the point towards the light,
+// and Distance is how far a shadow ray has to travel along it before
+// it reaches the light. Lights at infinity have an infinite Distance.
+type Sample struct {
+	Direction tuple.Vector
+	Distance  float64
+	Intensity canvas.Color
+}
+
+// OccludedFunc reports wether anything in the scene blocks a
+// shadow ray cast from the point, along the direction, before it
+// has travelled the distance.
+type OccludedFunc func(point tuple.Point, direction tuple.Vector, distance float64) bool
+
+// IntensityAt returns the fraction of the light that reaches the point,
+// from 0.0 when every sample of the light is occluded to 1.0 when none are.
+// Lights with a single sample are therefore either fully lit or fully
+// in shadow, while area lights cast soft shadows.
+// Jittered lights draw new samples on every call, so to shade with the
+// same samples the shadow was found with, use IntensityOf instead.
+func IntensityAt(l Light, point tuple.Point, occluded OccludedFunc) float64 {
+	return IntensityOf(l.Samples(point), point, occluded)
+}
+
+// IntensityOf returns the fraction of the samples that reach the point,
+// like IntensityAt, for samples already taken from a light.
+func IntensityOf(samples []Sample, point tuple.Point, occluded OccludedFunc) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	lit := 0
+	for _, s := range samples {
+		if !occluded(point, s.Direction, s.Distance) {
+			lit++
+		}
+	}
+	return float64(lit) / float64(len(samples))
+}
+
+// towards returns the Sample for light arriving at point from a position
+func towards(point, position tuple.Point, intensity canvas.Color) Sample {
+	v := position.Subtract(point)
+	distance := v.Magnitude()
+	return Sample{
+		Direction: v.Divide(distance),
+		Distance:  distance,
+		Intensity: intensity,
+	}
+}
+
+// PointLight is a light with no size, existing at a single point in space
+type PointLight struct {
+	Position  tuple.Point
+	intensity canvas.Color
+}
+
+// NewPointLight constructs a PointLight
+func NewPointLight(position tuple.Point, intensity canvas.Color) PointLight {
+	return PointLight{
+		Position:  position,
+		intensity: intensity,
+	}
+}
+
+// Samples returns the light arriving from the light's position
+func (l PointLight) Samples(point tuple.Point) []Sample {
+	return []Sample{towards(point, l.Position, l.intensity)}
+}
+
+// Intensity returns the color of the light
+func (l PointLight) Intensity() canvas.Color {
+	return l.intensity
+}
+
+// DirectionalLight is a light infinitely far away, like the sun,
+// whose rays all arrive in parallel.
+type DirectionalLight struct {
+	Direction tuple.Vector
+	intensity canvas.Color
+}
+
+// NewDirectionalLight constructs a DirectionalLight.
+// The direction is the way the light travels, not the way towards it.
+func NewDirectionalLight(direction tuple.Vector, intensity canvas.Color) DirectionalLight {
+	return DirectionalLight{
+		Direction: direction.Normalize(),
+		intensity: intensity,
+	}
+}
+
+// Samples returns the same light for every point, arriving from infinitely far away
+func (l DirectionalLight) Samples(point tuple.Point) []Sample {
+	return []Sample{{
+		Direction: l.Direction.Negate(),
+		Distance:  math.Inf(1),
+		Intensity: l.intensity,
+	}}
+}
+
+// Intensity returns the color of the light
+func (l DirectionalLight) Intensity() canvas.Color {
+	return l.intensity
+}
+
+// SpotLight is a point light that only shines within a cone.
+// Points within the inner angle of the cone are fully lit, and the light
+// falls off towards the outer angle, beyond which nothing is lit.
+// Falloff shapes the transition and must be greater than 0. 1.0 is
+// linear in the cosine of the angle, larger values make the edge softer
+// and values between 0 and 1 make it harder.
+type SpotLight struct {
+	Position  tuple.Point
+	Direction tuple.Vector
+	Inner     float64
+	Outer     float64
+	Falloff   float64
+	intensity canvas.Color
+}
+
+// NewSpotLight constructs a SpotLight. The angles are in radians,
+// measured from the direction to the edge of the cone.
+func NewSpotLight(position tuple.Point, direction tuple.Vector, inner, outer, falloff float64, intensity canvas.Color) (SpotLight, error) {
+	if inner < 0 || inner > outer || outer > math.Pi {
+		return SpotLight{}, errors.New("spot light angles must satisfy 0 <= inner <= outer <= π")
+	}
+	if falloff <= 0 {
+		return SpotLight{}, errors.New("spot light falloff must be greater than 0")
+	}
+	return SpotLight{
+		Position:  position,
+		Direction: direction.Normalize(),
+		Inner:     inner,
+		Outer:     outer,
+		Falloff:   falloff,
+		intensity: intensity,
+	}, nil
+}
+
+// Samples returns the light arriving from the light's position,
+// dimmed by how far outside of the inner cone the point is.
+func (l SpotLight) Samples(point tuple.Point) []Sample {
+	s := towards(point, l.Position, l.intensity)
+	s.Intensity = s.Intensity.Scale(l.cone(s.Direction.Negate()))
+	return []Sample{s}
+}
+
+// Intensity returns the color of the light
+func (l SpotLight) Intensity() canvas.Color {
+	return l.intensity
+}
+
+// cone returns how much of the light travels along
+// the unit vector v, from 0.0 to 1.0
+func (l SpotLight) cone(v tuple.Vector) float64 {
+	cos := v.Dot(l.Direction)
+	cosInner, cosOuter := math.Cos(l.Inner), math.Cos(l.Outer)
+	if cos >= cosInner {
+		return 1
+	}
+	if cos <= cosOuter {
+		return 0
+	}
+	return math.Pow((cos-cosOuter)/(cosInner-cosOuter), l.Falloff)
+}
@@ -0,0 +1,160 @@
+package light
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPointLight(t *testing.T) {
+	is := assert.New(t)
+
+	intensity := canvas.NewColor(1, 1, 1)
+	position := tuple.Pt(0, 0, 0)
+
+	l := NewPointLight(position, intensity)
+	is.Equal(position, l.Position)
+	is.Equal(intensity, l.Intensity())
+}
+
+func TestPointLightSamples(t *testing.T) {
+	is := assert.New(t)
+
+	l := NewPointLight(tuple.Pt(0, 10, 0), canvas.NewColor(1, 1, 1))
+	samples := l.Samples(tuple.Pt(0, 0, 0))
+
+	is.Len(samples, 1)
+	is.True(samples[0].Direction.Equal(tuple.Vec(0, 1, 0)))
+	is.Equal(10.0, samples[0].Distance)
+	is.Equal(canvas.NewColor(1, 1, 1), samples[0].Intensity)
+}
+
+func TestDirectionalLight(t *testing.T) {
+	is := assert.New(t)
+
+	l := NewDirectionalLight(tuple.Vec(0, -2, 0), canvas.NewColor(1, 1, 1))
+	is.True(l.Direction.Equal(tuple.Vec(0, -1, 0)))
+
+	// every point sees the same light, from infinitely far away
+	for _, p := range []tuple.Point{tuple.Pt(0, 0, 0), tuple.Pt(100, -50, 3)} {
+		samples := l.Samples(p)
+		is.Len(samples, 1)
+		is.True(samples[0].Direction.Equal(tuple.Vec(0, 1, 0)))
+		is.True(math.IsInf(samples[0].Distance, 1))
+		is.Equal(canvas.NewColor(1, 1, 1), samples[0].Intensity)
+	}
+}
+
+func TestSpotLight(t *testing.T) {
+	is := assert.New(t)
+
+	// shining straight down from y = 1
+	l, err := NewSpotLight(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0), math.Pi/8, math.Pi/4, 1, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+
+	// inside the inner cone
+	s := l.Samples(tuple.Pt(0.1, 0, 0))[0]
+	is.True(s.Intensity.Equal(canvas.NewColor(1, 1, 1)))
+
+	// beyond the outer cone
+	s = l.Samples(tuple.Pt(2, 0, 0))[0]
+	is.True(s.Intensity.Equal(canvas.NewColor(0, 0, 0)))
+
+	// in between, falling off linearly in the cosine
+	angle := 3 * math.Pi / 16
+	s = l.Samples(tuple.Pt(math.Tan(angle), 0, 0))[0]
+	e := (math.Cos(angle) - math.Cos(math.Pi/4)) / (math.Cos(math.Pi/8) - math.Cos(math.Pi/4))
+	is.True(float.Equal(e, s.Intensity.R()))
+
+	// a larger falloff dims the edge further
+	l.Falloff = 2
+	s = l.Samples(tuple.Pt(math.Tan(angle), 0, 0))[0]
+	is.True(float.Equal(e*e, s.Intensity.R()))
+
+	is.Equal(canvas.NewColor(1, 1, 1), l.Intensity())
+}
+
+func TestNewSpotLightValidation(t *testing.T) {
+	is := assert.New(t)
+
+	p := tuple.Pt(0, 1, 0)
+	v := tuple.Vec(0, -1, 0)
+	c := canvas.NewColor(1, 1, 1)
+
+	_, err := NewSpotLight(p, v, 0.3, 0.2, 1, c)
+	is.Error(err)
+	_, err = NewSpotLight(p, v, 0.1, 4, 1, c)
+	is.Error(err)
+	_, err = NewSpotLight(p, v, 0.1, 0.2, 0, c)
+	is.Error(err)
+	_, err = NewSpotLight(p, v, 0.1, 0.2, -1, c)
+	is.Error(err)
+}
+
+func TestIntensityAtPointLight(t *testing.T) {
+	is := assert.New(t)
+
+	l := NewPointLight(tuple.Pt(-10, 10, -10), canvas.NewColor(1, 1, 1))
+	point := tuple.Pt(0, 0, 0)
+
+	is.Equal(1.0, IntensityAt(l, point, func(p tuple.Point, d tuple.Vector, distance float64) bool { return false }))
+	is.Equal(0.0, IntensityAt(l, point, func(p tuple.Point, d tuple.Vector, distance float64) bool { return true }))
+}
+
+func TestIntensityAtDirectionalLight(t *testing.T) {
+	is := assert.New(t)
+
+	l := NewDirectionalLight(tuple.Vec(0, -1, 0), canvas.NewColor(1, 1, 1))
+
+	// an infinite ceiling at y = 5 blocks the sun
+	ceiling := func(p tuple.Point, d tuple.Vector, distance float64) bool {
+		h := (5 - p.Y) / d.Y
+		return h > 0 && h < distance
+	}
+	is.Equal(0.0, IntensityAt(l, tuple.Pt(0, 0, 0), ceiling))
+	is.Equal(1.0, IntensityAt(l, tuple.Pt(0, 6, 0), ceiling))
+}
+
+func TestIntensityAtAreaLight(t *testing.T) {
+	is := assert.New(t)
+
+	corner := tuple.Pt(-0.5, -0.5, -5)
+	l, err := NewAreaLight(corner, tuple.Vec(1, 0, 0), 2, tuple.Vec(0, 1, 0), 2, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+
+	// a wall that blocks everything left of x = 0
+	wall := func(p tuple.Point, d tuple.Vector, distance float64) bool {
+		return d.X < 0
+	}
+	is.Equal(0.5, IntensityAt(l, tuple.Pt(0, 0, 2), wall))
+}
+
+func TestIntensityOfSharedSamples(t *testing.T) {
+	is := assert.New(t)
+
+	corner := tuple.Pt(-0.5, -0.5, -5)
+	l, err := NewAreaLight(corner, tuple.Vec(1, 0, 0), 4, tuple.Vec(0, 1, 0), 4, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+	l.Jitter = rand.New(rand.NewSource(1))
+
+	// the fraction is counted over exactly the samples given
+	point := tuple.Pt(0, 0, 2)
+	samples := l.Samples(point)
+	wall := func(p tuple.Point, d tuple.Vector, distance float64) bool {
+		return d.X < 0
+	}
+	lit := 0
+	for _, s := range samples {
+		if s.Direction.X >= 0 {
+			lit++
+		}
+	}
+	is.Equal(float64(lit)/16, IntensityOf(samples, point, wall))
+	is.Equal(0.0, IntensityOf(nil, point, wall))
+}
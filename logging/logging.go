@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Logger is the structured logger used throughout the ray tracer.
+// Messages are followed by alternating key/value pairs,
+// which means a *slog.Logger can be passed in directly.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// Discard is a Logger that throws every message away.
+// It is used wherever no Logger has been injected, keeping the library silent by default.
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debug(msg string, args ...interface{}) {}
+func (discard) Info(msg string, args ...interface{})  {}
+func (discard) Error(msg string, args ...interface{}) {}
+
+// OrDiscard returns l, or Discard if l is nil
+func OrDiscard(l Logger) Logger {
+	if l == nil {
+		return Discard
+	}
+	return l
+}
+
+// textLogger writes key=value lines to an io.Writer
+type textLogger struct {
+	out   *log.Logger
+	debug bool
+}
+
+// New returns a Logger that writes one line per message to w,
+// formatted as `level=INFO msg="..." key=value`.
+// Debug messages are only written when debug is true.
+func New(w io.Writer, debug bool) Logger {
+	return textLogger{
+		out:   log.New(w, "", log.LstdFlags),
+		debug: debug,
+	}
+}
+
+func (l textLogger) Debug(msg string, args ...interface{}) {
+	if l.debug {
+		l.write("DEBUG", msg, args)
+	}
+}
+
+func (l textLogger) Info(msg string, args ...interface{}) {
+	l.write("INFO", msg, args)
+}
+
+func (l textLogger) Error(msg string, args ...interface{}) {
+	l.write("ERROR", msg, args)
+}
+
+func (l textLogger) write(level, msg string, args []interface{}) {
+	l.out.Print(format(level, msg, args))
+}
+
+func format(level, msg string, args []interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%q", level, msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&b, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrDiscard(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(Discard, OrDiscard(nil))
+
+	l := New(&bytes.Buffer{}, false)
+	is.Equal(l, OrDiscard(l))
+}
+
+func TestTextLogger(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	l := New(&buf, false)
+
+	l.Info("render finished", "width", 10, "height", 20)
+	is.Contains(buf.String(), `level=INFO msg="render finished" width=10 height=20`)
+
+	buf.Reset()
+	l.Debug("write pixel", "x", 1)
+	is.Empty(buf.String())
+
+	l = New(&buf, true)
+	l.Debug("write pixel", "x", 1)
+	is.Contains(buf.String(), `level=DEBUG msg="write pixel" x=1`)
+}
+
+func TestTextLoggerOddArguments(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	l := New(&buf, false)
+
+	l.Error("oops", "err")
+	is.Contains(buf.String(), `level=ERROR msg="oops" !BADKEY=err`)
+}
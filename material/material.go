@@ -0,0 +1,75 @@
+package material
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/light"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Material describes the surface of an object
+// using the attributes of the Phong reflection model.
+// Emissive is the light given off by the surface itself,
+// which only the path tracer takes into account.
+type Material struct {
+	Color     canvas.Color
+	Ambient   float64
+	Diffuse   float64
+	Specular  float64
+	Shininess float64
+	Emissive  canvas.Color
+}
+
+// New returns the default material
+func New() Material {
+	return Material{
+		Color:     canvas.NewColor(1, 1, 1),
+		Ambient:   0.1,
+		Diffuse:   0.9,
+		Specular:  0.9,
+		Shininess: 200.0,
+		Emissive:  canvas.NewColor(0, 0, 0),
+	}
+}
+
+// Lighting shades a point on a surface using the Phong reflection model.
+// intensity is the fraction of the light that reaches the point, as
+// returned by light.IntensityAt, and scales the diffuse and specular
+// contributions. Ambient light is unaffected by shadows.
+// The diffuse and specular contributions are averaged over every sample of the light.
+func Lighting(m Material, l light.Light, point tuple.Point, eyev, normalv tuple.Vector, intensity float64) canvas.Color {
+	return LightingSamples(m, l, l.Samples(point), point, eyev, normalv, intensity)
+}
+
+// LightingSamples shades a point like Lighting, using samples already
+// taken from the light. Jittered lights draw new samples every time, so
+// taking them once and passing the same samples to light.IntensityOf
+// finds the shadow at the same points on the light that are shaded.
+func LightingSamples(m Material, l light.Light, samples []light.Sample, point tuple.Point, eyev, normalv tuple.Vector, intensity float64) canvas.Color {
+	ambient := m.Color.Multiply(l.Intensity()).Scale(m.Ambient)
+
+	if len(samples) == 0 {
+		return ambient
+	}
+
+	sum := canvas.NewColor(0, 0, 0)
+	for _, s := range samples {
+		lightDotNormal := s.Direction.Dot(normalv)
+		// a negative dot product means the light is on the other side of the surface
+		if lightDotNormal < 0 {
+			continue
+		}
+		effective := m.Color.Multiply(s.Intensity)
+		sum = sum.Add(effective.Scale(m.Diffuse * lightDotNormal))
+
+		reflectDotEye := s.Direction.Negate().Reflect(normalv).Dot(eyev)
+		// a negative dot product means the light reflects away from the eye
+		if reflectDotEye > 0 {
+			factor := math.Pow(reflectDotEye, m.Shininess)
+			sum = sum.Add(s.Intensity.Scale(m.Specular * factor))
+		}
+	}
+
+	return ambient.Add(sum.Scale(intensity / float64(len(samples))))
+}
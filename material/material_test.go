@@ -0,0 +1,141 @@
+package material
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/light"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMaterial(t *testing.T) {
+	is := assert.New(t)
+
+	m := New()
+	is.Equal(canvas.NewColor(1, 1, 1), m.Color)
+	is.Equal(0.1, m.Ambient)
+	is.Equal(0.9, m.Diffuse)
+	is.Equal(0.9, m.Specular)
+	is.Equal(200.0, m.Shininess)
+	is.Equal(canvas.NewColor(0, 0, 0), m.Emissive)
+}
+
+func TestLighting(t *testing.T) {
+	m := New()
+	position := tuple.Pt(0, 0, 0)
+	normalv := tuple.Vec(0, 0, -1)
+	s2 := math.Sqrt(2) / 2
+
+	tests := []struct {
+		name     string
+		eyev     tuple.Vector
+		light    tuple.Point
+		expected canvas.Color
+	}{
+		{"eye between the light and the surface", tuple.Vec(0, 0, -1), tuple.Pt(0, 0, -10), canvas.NewColor(1.9, 1.9, 1.9)},
+		{"eye offset 45°", tuple.Vec(0, s2, -s2), tuple.Pt(0, 0, -10), canvas.NewColor(1.0, 1.0, 1.0)},
+		{"light offset 45°", tuple.Vec(0, 0, -1), tuple.Pt(0, 10, -10), canvas.NewColor(0.7364, 0.7364, 0.7364)},
+		{"eye in the path of the reflection", tuple.Vec(0, -s2, -s2), tuple.Pt(0, 10, -10), canvas.NewColor(1.6364, 1.6364, 1.6364)},
+		{"light behind the surface", tuple.Vec(0, 0, -1), tuple.Pt(0, 0, 10), canvas.NewColor(0.1, 0.1, 0.1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := assert.New(t)
+			l := light.NewPointLight(tt.light, canvas.NewColor(1, 1, 1))
+			res := Lighting(m, l, position, tt.eyev, normalv, 1.0)
+			is.True(res.Equal(tt.expected), "got %v", res)
+		})
+	}
+}
+
+func TestLightingInShadow(t *testing.T) {
+	is := assert.New(t)
+
+	m := New()
+	l := light.NewPointLight(tuple.Pt(0, 0, -10), canvas.NewColor(1, 1, 1))
+	res := Lighting(m, l, tuple.Pt(0, 0, 0), tuple.Vec(0, 0, -1), tuple.Vec(0, 0, -1), 0.0)
+	is.True(res.Equal(canvas.NewColor(0.1, 0.1, 0.1)))
+}
+
+func TestLightingSamplesAreaLight(t *testing.T) {
+	is := assert.New(t)
+
+	corner := tuple.Pt(-0.5, -0.5, -5)
+	l, _ := light.NewAreaLight(corner, tuple.Vec(1, 0, 0), 2, tuple.Vec(0, 1, 0), 2, canvas.NewColor(1, 1, 1))
+
+	m := New()
+	m.Ambient = 0.1
+	m.Diffuse = 0.9
+	m.Specular = 0
+	m.Color = canvas.NewColor(1, 1, 1)
+
+	eye := tuple.Pt(0, 0, -5)
+	tests := []struct {
+		point    tuple.Point
+		expected canvas.Color
+	}{
+		{tuple.Pt(0, 0, -1), canvas.NewColor(0.9965, 0.9965, 0.9965)},
+		{tuple.Pt(0, 0.7071, -0.7071), canvas.NewColor(0.62318, 0.62318, 0.62318)},
+	}
+	for _, tt := range tests {
+		// the point sits on a unit sphere, so its normal is its position
+		normalv := tt.point.Subtract(tuple.Pt(0, 0, 0)).Normalize()
+		eyev := eye.Subtract(tt.point).Normalize()
+		res := Lighting(m, l, tt.point, eyev, normalv, 1.0)
+		is.True(res.Equal(tt.expected), "got %v", res)
+	}
+
+	// half of the light is blocked
+	res := Lighting(m, l, tuple.Pt(0, 0, -1), tuple.Vec(0, 0, -1), tuple.Vec(0, 0, -1), 0.5)
+	is.True(res.R() > 0.1 && res.R() < 0.9965)
+}
+
+func TestLightingSpotLight(t *testing.T) {
+	is := assert.New(t)
+
+	m := New()
+	point := tuple.Pt(0, 0, 0)
+	eyev := tuple.Vec(0, 0, -1)
+	normalv := tuple.Vec(0, 0, -1)
+
+	// pointing straight at the point
+	l, _ := light.NewSpotLight(tuple.Pt(0, 0, -10), tuple.Vec(0, 0, 1), math.Pi/8, math.Pi/4, 1, canvas.NewColor(1, 1, 1))
+	res := Lighting(m, l, point, eyev, normalv, 1.0)
+	is.True(res.Equal(canvas.NewColor(1.9, 1.9, 1.9)))
+
+	// pointing away, leaving only ambient light
+	l, _ = light.NewSpotLight(tuple.Pt(0, 0, -10), tuple.Vec(0, 1, 0), math.Pi/8, math.Pi/4, 1, canvas.NewColor(1, 1, 1))
+	res = Lighting(m, l, point, eyev, normalv, 1.0)
+	is.True(res.Equal(canvas.NewColor(0.1, 0.1, 0.1)))
+}
+
+func TestLightingSamples(t *testing.T) {
+	is := assert.New(t)
+
+	m := New()
+	point := tuple.Pt(0, 0, 0)
+	eyev := tuple.Vec(0, 0, -1)
+	normalv := tuple.Vec(0, 0, -1)
+	l, err := light.NewAreaLight(tuple.Pt(-1, -1, -5), tuple.Vec(2, 0, 0), 2, tuple.Vec(0, 2, 0), 2, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+
+	samples := l.Samples(point)
+	is.True(LightingSamples(m, l, samples, point, eyev, normalv, 0.5).Equal(Lighting(m, l, point, eyev, normalv, 0.5)))
+
+	// only the samples given are shaded
+	is.True(LightingSamples(m, l, nil, point, eyev, normalv, 1).Equal(canvas.NewColor(0.1, 0.1, 0.1)))
+	one := LightingSamples(m, l, samples[:1], point, eyev, normalv, 1)
+	is.True(one.Equal(LightingSamples(m, l, []light.Sample{samples[0], samples[0]}, point, eyev, normalv, 1)))
+}
+
+func TestLightingDirectionalLight(t *testing.T) {
+	is := assert.New(t)
+
+	m := New()
+	l := light.NewDirectionalLight(tuple.Vec(0, 0, 1), canvas.NewColor(1, 1, 1))
+	res := Lighting(m, l, tuple.Pt(0, 0, 0), tuple.Vec(0, 0, -1), tuple.Vec(0, 0, -1), 1.0)
+	is.True(res.Equal(canvas.NewColor(1.9, 1.9, 1.9)))
+}
@@ -0,0 +1,177 @@
+package material
+
+import (
+	"errors"
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// dielectricF0 is how much light non-metals reflect
+// when looked at straight on, the same 4% that glTF uses.
+const dielectricF0 = 0.04
+
+// minAlpha stops a perfectly smooth surface from turning the
+// microfacet distribution into an infinitely thin spike.
+const minAlpha = 0.001
+
+// PBR is a physically based material, following the metallic-roughness
+// model of glTF. BaseColor is the diffuse color of non-metals and the
+// reflected color of metals. Metallic blends between the two, and
+// Roughness spreads out the reflection, both from 0.0 to 1.0.
+// Reflections use the GGX (Trowbridge-Reitz) microfacet distribution
+// with the Smith shadowing term and Schlick's Fresnel approximation.
+type PBR struct {
+	BaseColor canvas.Color
+	Metallic  float64
+	Roughness float64
+	Emissive  canvas.Color
+}
+
+// NewPBR returns a PBR material that gives off no light.
+// It returns an error if metallic or roughness are outside 0.0 to 1.0.
+func NewPBR(baseColor canvas.Color, metallic, roughness float64) (PBR, error) {
+	if metallic < 0 || metallic > 1 {
+		return PBR{}, errors.New("metallic must be between 0.0 and 1.0")
+	}
+	if roughness < 0 || roughness > 1 {
+		return PBR{}, errors.New("roughness must be between 0.0 and 1.0")
+	}
+	return PBR{
+		BaseColor: baseColor,
+		Metallic:  metallic,
+		Roughness: roughness,
+		Emissive:  canvas.NewColor(0, 0, 0),
+	}, nil
+}
+
+// Emission returns the material's Emissive color
+func (m PBR) Emission() canvas.Color {
+	return m.Emissive
+}
+
+// BRDF adds the specular reflection of the microfacets to the diffuse
+// reflection of whatever light the Fresnel term lets through.
+func (m PBR) BRDF(normal, wo, wi tuple.Vector) canvas.Color {
+	nl := normal.Dot(wi)
+	nv := normal.Dot(wo)
+	if nl <= 0 || nv <= 0 {
+		return canvas.NewColor(0, 0, 0)
+	}
+	a := m.alpha()
+	h := wo.Add(wi).Normalize()
+	f := schlick(m.f0(), wo.Dot(h))
+
+	specular := f.Scale(ggx(normal.Dot(h), a) * smith(nl, nv, a) / (4 * nl * nv))
+	diffuse := canvas.NewColor(1, 1, 1).Subtract(f).
+		Multiply(m.BaseColor).
+		Scale((1 - m.Metallic) / math.Pi)
+	return diffuse.Add(specular)
+}
+
+// Sample picks either a microfacet to reflect off, or a diffuse bounce,
+// and weighs the result by the combined probability of both.
+func (m PBR) Sample(normal, wo tuple.Vector, u1, u2 float64) (tuple.Vector, canvas.Color, bool) {
+	a := m.alpha()
+	chance := m.specularChance()
+
+	// reuse u1 to pick the lobe, stretching what is left back to 0.0 to 1.0
+	var wi tuple.Vector
+	if u1 < chance {
+		h := ggxHalfVector(normal, a, u1/chance, u2)
+		wi = wo.Negate().Reflect(h)
+	} else {
+		wi = cosineHemisphere(normal, (u1-chance)/(1-chance), u2)
+	}
+
+	nl := normal.Dot(wi)
+	if nl <= 0 {
+		return wi, canvas.NewColor(0, 0, 0), false
+	}
+	pdf := m.PDF(normal, wo, wi)
+	if pdf <= 0 {
+		return wi, canvas.NewColor(0, 0, 0), false
+	}
+	return wi, m.BRDF(normal, wo, wi).Scale(nl / pdf), true
+}
+
+// PDF combines the probabilities of both ways Sample can pick wi
+func (m PBR) PDF(normal, wo, wi tuple.Vector) float64 {
+	nl := normal.Dot(wi)
+	if nl <= 0 {
+		return 0
+	}
+	chance := m.specularChance()
+	return chance*specularPDF(normal, wo, wi, m.alpha()) + (1-chance)*nl/math.Pi
+}
+
+// alpha is the width of the GGX distribution, which glTF
+// defines as the square of the roughness.
+func (m PBR) alpha() float64 {
+	return math.Max(m.Roughness*m.Roughness, minAlpha)
+}
+
+// f0 is the color reflected when looking straight at the surface
+func (m PBR) f0() canvas.Color {
+	dielectric := canvas.NewColor(dielectricF0, dielectricF0, dielectricF0)
+	return dielectric.Scale(1 - m.Metallic).Add(m.BaseColor.Scale(m.Metallic))
+}
+
+// specularChance is how often Sample reflects off a microfacet.
+// Metals have no diffuse reflection, so they always do.
+func (m PBR) specularChance() float64 {
+	return 0.5 + 0.5*m.Metallic
+}
+
+// ggx is the fraction of microfacets facing along a half vector
+// at an angle with the given cosine from the normal.
+func ggx(cos, a float64) float64 {
+	if cos <= 0 {
+		return 0
+	}
+	a2 := a * a
+	d := cos*cos*(a2-1) + 1
+	return a2 / (math.Pi * d * d)
+}
+
+// smith is the fraction of microfacets that are neither hidden
+// from the light nor from the viewer by other microfacets.
+func smith(nl, nv, a float64) float64 {
+	a2 := a * a
+	g1 := func(cos float64) float64 {
+		return 2 * cos / (cos + math.Sqrt(a2+(1-a2)*cos*cos))
+	}
+	return g1(nl) * g1(nv)
+}
+
+// schlick approximates how much more light is reflected
+// at grazing angles than when looking straight on.
+func schlick(f0 canvas.Color, cos float64) canvas.Color {
+	k := math.Pow(1-math.Max(cos, 0), 5)
+	return f0.Add(canvas.NewColor(1, 1, 1).Subtract(f0).Scale(k))
+}
+
+// ggxHalfVector turns two uniform random numbers into a microfacet
+// normal, distributed in proportion to ggx times its cosine.
+func ggxHalfVector(normal tuple.Vector, a, u1, u2 float64) tuple.Vector {
+	a2 := a * a
+	cos := math.Sqrt((1 - u1) / (1 + (a2-1)*u1))
+	sin := math.Sqrt(1 - cos*cos)
+	sinPhi, cosPhi := math.Sincos(2 * math.Pi * u2)
+	u, v := tuple.OrthonormalBasis(normal)
+	return u.Scale(sin * cosPhi).
+		Add(v.Scale(sin * sinPhi)).
+		Add(normal.Scale(cos))
+}
+
+// specularPDF is the probability of ggxHalfVector reflecting wo into wi
+func specularPDF(normal, wo, wi tuple.Vector, a float64) float64 {
+	h := wo.Add(wi).Normalize()
+	oh := wo.Dot(h)
+	if oh <= 0 {
+		return 0
+	}
+	nh := normal.Dot(h)
+	return ggx(nh, a) * nh / (4 * oh)
+}
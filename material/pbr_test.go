@@ -0,0 +1,147 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPBR(t *testing.T) {
+	is := assert.New(t)
+
+	m, err := NewPBR(canvas.NewColor(1, 0.5, 0), 0.25, 0.75)
+	is.NoError(err)
+	is.Equal(canvas.NewColor(1, 0.5, 0), m.BaseColor)
+	is.Equal(0.25, m.Metallic)
+	is.Equal(0.75, m.Roughness)
+	is.Equal(canvas.NewColor(0, 0, 0), m.Emission())
+
+	_, err = NewPBR(canvas.NewColor(1, 1, 1), 1.5, 0.5)
+	is.Error(err)
+	_, err = NewPBR(canvas.NewColor(1, 1, 1), 0.5, -0.1)
+	is.Error(err)
+}
+
+func TestGGXIsNormalized(t *testing.T) {
+	is := assert.New(t)
+
+	// the projected area of all microfacets adds up to the area of the surface,
+	// integrating ggx(cos) * cos over the hemisphere with the midpoint rule
+	for _, a := range []float64{0.1, 0.5, 1} {
+		const steps = 100000
+		sum := 0.0
+		for i := 0; i < steps; i++ {
+			theta := (float64(i) + 0.5) / steps * math.Pi / 2
+			cos := math.Cos(theta)
+			sum += ggx(cos, a) * cos * math.Sin(theta) * 2 * math.Pi * (math.Pi / 2 / steps)
+		}
+		is.InDelta(1.0, sum, 0.001)
+	}
+}
+
+func TestPBRFresnel(t *testing.T) {
+	is := assert.New(t)
+
+	// looking straight on, non-metals reflect 4% and metals their base color
+	is.True(schlick(canvas.NewColor(0.04, 0.04, 0.04), 1).Equal(canvas.NewColor(0.04, 0.04, 0.04)))
+	is.True(schlick(canvas.NewColor(1, 0.5, 0), 1).Equal(canvas.NewColor(1, 0.5, 0)))
+	// at grazing angles everything is a mirror
+	is.True(schlick(canvas.NewColor(0.04, 0.04, 0.04), 0).Equal(canvas.NewColor(1, 1, 1)))
+
+	m, _ := NewPBR(canvas.NewColor(1, 0.5, 0), 0, 0.5)
+	is.True(m.f0().Equal(canvas.NewColor(0.04, 0.04, 0.04)))
+	m.Metallic = 1
+	is.True(m.f0().Equal(canvas.NewColor(1, 0.5, 0)))
+}
+
+func TestPBRBRDF(t *testing.T) {
+	is := assert.New(t)
+
+	m, _ := NewPBR(canvas.NewColor(1, 1, 1), 0, 0.5)
+	normal := tuple.Vec(0, 1, 0)
+	wo := tuple.Vec(1, 1, 0).Normalize()
+	wi := tuple.Vec(-1, 2, 1).Normalize()
+
+	// light is reflected the same both ways
+	is.True(m.BRDF(normal, wo, wi).Equal(m.BRDF(normal, wi, wo)))
+
+	// nothing is reflected from below the surface
+	is.True(m.BRDF(normal, wo, tuple.Vec(0, -1, 0)).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(m.BRDF(normal, tuple.Vec(0, -1, 0), wi).Equal(canvas.NewColor(0, 0, 0)))
+
+	// the reflection is brightest in the mirror direction
+	mirror := wo.Negate().Reflect(normal)
+	is.True(m.BRDF(normal, wo, mirror).R() > m.BRDF(normal, wo, wi).R())
+
+	// metals have no diffuse reflection, so they are black away from the highlight
+	m.Metallic = 1
+	m.Roughness = 0.1
+	is.True(m.BRDF(normal, wo, tuple.Vec(0, 1, 1).Normalize()).R() < 0.001)
+}
+
+func TestPBRSample(t *testing.T) {
+	is := assert.New(t)
+
+	normal := tuple.Vec(0, 1, 0)
+	wo := tuple.Vec(1, 2, 0).Normalize()
+	rnd := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		metallic  float64
+		roughness float64
+	}{
+		{0, 1},
+		{0, 0.5},
+		{0, 0.1},
+		{1, 0.5},
+		{0.5, 0.3},
+	}
+	for _, tt := range tests {
+		m, _ := NewPBR(canvas.NewColor(1, 1, 1), tt.metallic, tt.roughness)
+
+		// the average weight of the samples estimates the total light reflected,
+		// and must agree with integrating the BRDF over uniformly picked directions
+		const n = 200000
+		sampled, uniform := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			wi, weight, ok := m.Sample(normal, wo, rnd.Float64(), rnd.Float64())
+			if ok {
+				is.True(float.Equal(1, wi.Magnitude()))
+				is.True(weight.Equal(m.BRDF(normal, wo, wi).Scale(wi.Dot(normal) / m.PDF(normal, wo, wi))))
+				sampled += weight.R()
+			}
+
+			z := rnd.Float64()
+			r := math.Sqrt(1 - z*z)
+			sin, cos := math.Sincos(2 * math.Pi * rnd.Float64())
+			d := tuple.Vec(r*cos, z, r*sin)
+			uniform += m.BRDF(normal, wo, d).R() * z * 2 * math.Pi
+		}
+		sampled /= n
+		uniform /= n
+
+		// no energy is created
+		is.True(sampled <= 1.01, "metallic %v roughness %v reflects %v", tt.metallic, tt.roughness, sampled)
+		is.InDelta(uniform, sampled, 0.03, "metallic %v roughness %v", tt.metallic, tt.roughness)
+	}
+}
+
+func TestPBRSampleSmoothMetal(t *testing.T) {
+	is := assert.New(t)
+
+	// a smooth metal is a mirror tinted by its base color
+	m, _ := NewPBR(canvas.NewColor(1, 0.5, 0), 1, 0)
+	normal := tuple.Vec(0, 1, 0)
+	wo := tuple.Vec(1, 1, 0).Normalize()
+
+	wi, weight, ok := m.Sample(normal, wo, 0.3, 0.7)
+	is.True(ok)
+	is.True(wi.EqualWithin(tuple.Vec(-1, 1, 0).Normalize(), float.Tolerance{Abs: 0.01}))
+	is.True(weight.EqualWithin(schlick(m.f0(), wo.Dot(normal)), float.Tolerance{Abs: 0.01}))
+}
@@ -0,0 +1,72 @@
+package material
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Surface describes how light scatters off a material, for renderers
+// such as the path tracer that follow light physically.
+// All directions are unit vectors pointing away from the surface:
+// normal is on the same side as wo, the direction towards the viewer,
+// and wi is the direction towards the incoming light.
+type Surface interface {
+	// Emission is the light given off by the surface itself
+	Emission() canvas.Color
+	// BRDF returns the fraction of light arriving from wi that leaves
+	// towards wo, per unit of solid angle.
+	BRDF(normal, wo, wi tuple.Vector) canvas.Color
+	// Sample turns two uniform random numbers into a direction wi for the
+	// light to arrive from, chosen roughly in proportion to how much it
+	// contributes. The weight is the BRDF times the cosine of wi, divided
+	// by the probability of picking wi. It returns false if no light
+	// is scattered.
+	Sample(normal, wo tuple.Vector, u1, u2 float64) (tuple.Vector, canvas.Color, bool)
+	// PDF returns the probability density, per unit of solid angle,
+	// of Sample picking wi.
+	PDF(normal, wo, wi tuple.Vector) float64
+}
+
+// Emission returns the material's Emissive color
+func (m Material) Emission() canvas.Color {
+	return m.Emissive
+}
+
+// BRDF treats the Phong material as a perfectly diffuse surface
+// reflecting Diffuse of its Color. The specular highlight has no
+// physical equivalent, so it is ignored.
+func (m Material) BRDF(normal, wo, wi tuple.Vector) canvas.Color {
+	if wi.Dot(normal) <= 0 {
+		return canvas.NewColor(0, 0, 0)
+	}
+	return m.albedo().Scale(1 / math.Pi)
+}
+
+// Sample picks a cosine-weighted direction, which cancels out
+// the cosine and the 1/π of the diffuse BRDF.
+func (m Material) Sample(normal, wo tuple.Vector, u1, u2 float64) (tuple.Vector, canvas.Color, bool) {
+	return cosineHemisphere(normal, u1, u2), m.albedo(), true
+}
+
+// PDF of a cosine-weighted direction
+func (m Material) PDF(normal, wo, wi tuple.Vector) float64 {
+	return math.Max(wi.Dot(normal), 0) / math.Pi
+}
+
+func (m Material) albedo() canvas.Color {
+	return m.Color.Scale(m.Diffuse)
+}
+
+// cosineHemisphere turns two uniform random numbers into a direction
+// on the hemisphere around the normal, more likely close to the normal
+// in proportion to the cosine of the angle between them.
+func cosineHemisphere(normal tuple.Vector, u1, u2 float64) tuple.Vector {
+	r := math.Sqrt(u1)
+	sin, cos := math.Sincos(2 * math.Pi * u2)
+	u, v := tuple.OrthonormalBasis(normal)
+	return u.Scale(r * cos).
+		Add(v.Scale(r * sin)).
+		Add(normal.Scale(math.Sqrt(1 - u1)))
+}
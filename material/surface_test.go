@@ -0,0 +1,53 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterialSurface(t *testing.T) {
+	is := assert.New(t)
+
+	var s Surface = New()
+	normal := tuple.Vec(0, 1, 0)
+	wo := tuple.Vec(0, 1, 0)
+
+	is.Equal(canvas.NewColor(0, 0, 0), s.Emission())
+
+	// a perfectly diffuse surface reflects Diffuse of its color in every direction
+	f := s.BRDF(normal, wo, tuple.Vec(1, 1, 0).Normalize())
+	is.True(f.Equal(canvas.NewColor(0.9/math.Pi, 0.9/math.Pi, 0.9/math.Pi)))
+	is.True(s.BRDF(normal, wo, tuple.Vec(0, -1, 0)).Equal(canvas.NewColor(0, 0, 0)))
+
+	wi, weight, ok := s.Sample(normal, wo, 0.3, 0.6)
+	is.True(ok)
+	is.True(wi.Dot(normal) > 0)
+	is.True(weight.Equal(canvas.NewColor(0.9, 0.9, 0.9)))
+	is.True(float.Equal(wi.Dot(normal)/math.Pi, s.PDF(normal, wo, wi)))
+	is.Equal(0.0, s.PDF(normal, wo, tuple.Vec(0, -1, 0)))
+}
+
+func TestCosineHemisphere(t *testing.T) {
+	is := assert.New(t)
+
+	normal := tuple.Vec(1, 2, 3).Normalize()
+	rnd := rand.New(rand.NewSource(1))
+
+	// the average cosine of a cosine-weighted hemisphere is 2/3
+	const n = 10000
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		d := cosineHemisphere(normal, rnd.Float64(), rnd.Float64())
+		is.True(float.Equal(1, d.Magnitude()))
+		is.True(d.Dot(normal) >= 0)
+		sum += d.Dot(normal)
+	}
+	is.InDelta(2.0/3.0, sum/n, 0.01)
+}
@@ -0,0 +1,141 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// LU is the LU decomposition of a square matrix with partial pivoting,
+// such that P*A = L*U. L (with an implicit unit diagonal) and U are
+// stored together in a single matrix, and P is stored as a permutation
+// of the row indices.
+type LU struct {
+	lu       Matrix
+	pivot    []int
+	sign     float64
+	singular bool
+}
+
+// singularTolerance is the size, relative to the largest value in the
+// matrix, below which a pivot is considered zero. Being relative, it
+// treats a matrix and the same matrix scaled by 0.01 alike.
+const singularTolerance = 1e-12
+
+// Decompose returns the LU decomposition of a square matrix.
+// Rows are swapped so that the largest remaining value in each column is
+// used as the pivot, which keeps the decomposition numerically stable.
+func (m Matrix) Decompose() (LU, error) {
+	if err := m.validateSquare("decompose"); err != nil {
+		return LU{}, err
+	}
+	n := m.rows()
+	lu := NewMatrix(n, n)
+	for r := range m {
+		copy(lu[r], m[r])
+	}
+	pivot := make([]int, n)
+	for i := range pivot {
+		pivot[i] = i
+	}
+	sign := 1.0
+	singular := false
+	largest := 0.0
+	for r := range m {
+		for _, v := range m[r] {
+			largest = math.Max(largest, math.Abs(v))
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		// find the pivot row
+		p := k
+		for r := k + 1; r < n; r++ {
+			if math.Abs(lu[r][k]) > math.Abs(lu[p][k]) {
+				p = r
+			}
+		}
+		if p != k {
+			lu[p], lu[k] = lu[k], lu[p]
+			pivot[p], pivot[k] = pivot[k], pivot[p]
+			sign = -sign
+		}
+		if math.Abs(lu[k][k]) <= singularTolerance*largest {
+			singular = true
+			continue
+		}
+		// eliminate below the pivot
+		for r := k + 1; r < n; r++ {
+			lu[r][k] /= lu[k][k]
+			for c := k + 1; c < n; c++ {
+				lu[r][c] -= lu[r][k] * lu[k][c]
+			}
+		}
+	}
+	return LU{lu: lu, pivot: pivot, sign: sign, singular: singular}, nil
+}
+
+// Singular reports wether the decomposed matrix has no inverse
+func (d LU) Singular() bool {
+	return d.singular
+}
+
+// Determinant returns the product of the diagonal of U,
+// negated for every row swap that was made.
+func (d LU) Determinant() float64 {
+	det := d.sign
+	for i := range d.lu {
+		det *= d.lu[i][i]
+	}
+	return det
+}
+
+// Solve returns x for the system of linear equations A*x = b
+func (d LU) Solve(b []float64) ([]float64, error) {
+	n := len(d.lu)
+	if len(b) != n {
+		return nil, fmt.Errorf("cannot solve a %dx%d system for %d values: %w", n, n, len(b), ErrDimension)
+	}
+	if d.singular {
+		return nil, fmt.Errorf("cannot solve a singular system")
+	}
+	// forward substitution with L, applying the row swaps to b
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = b[d.pivot[i]]
+		for j := 0; j < i; j++ {
+			x[i] -= d.lu[i][j] * x[j]
+		}
+	}
+	// back substitution with U
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= d.lu[i][j] * x[j]
+		}
+		x[i] /= d.lu[i][i]
+	}
+	return x, nil
+}
+
+// Inverse solves for every column of the identity matrix
+func (d LU) Inverse() (Matrix, error) {
+	n := len(d.lu)
+	if d.singular {
+		return Matrix{}, fmt.Errorf("matrix is not invertible")
+	}
+	inv := NewMatrix(n, n)
+	e := make([]float64, n)
+	for c := 0; c < n; c++ {
+		for i := range e {
+			e[i] = 0
+		}
+		e[c] = 1
+		col, err := d.Solve(e)
+		if err != nil {
+			return Matrix{}, err
+		}
+		for r := range col {
+			inv[r][c] = col[r]
+		}
+	}
+	return inv, nil
+}
@@ -0,0 +1,231 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompose(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{-2, -8, 3, 5},
+		{-3, 1, 7, 3},
+		{1, 2, -9, 6},
+		{-6, 7, 7, -9},
+	}
+	d, err := a.Decompose()
+	is.NoError(err)
+	is.False(d.Singular())
+	is.True(float.Equal(-4071.0, d.Determinant()))
+
+	// the decomposition leaves the original untouched
+	is.Equal(-2.0, a[0][0])
+
+	_, err = Matrix{{1, 2, 3}, {4, 5, 6}}.Decompose()
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestDecomposeNeedsPivoting(t *testing.T) {
+	is := assert.New(t)
+
+	// a zero in the top left corner cannot be used as a pivot
+	a := Matrix{
+		{0, 1},
+		{1, 0},
+	}
+	d, err := a.Decompose()
+	is.NoError(err)
+	is.False(d.Singular())
+	is.True(float.Equal(-1.0, d.Determinant()))
+}
+
+func TestDecomposeSingular(t *testing.T) {
+	is := assert.New(t)
+
+	b := Matrix{
+		{-4, 2, -2, -3},
+		{9, 6, 2, 6},
+		{0, -5, 1, -5},
+		{0, 0, 0, 0},
+	}
+	d, err := b.Decompose()
+	is.NoError(err)
+	is.True(d.Singular())
+	is.Equal(0.0, d.Determinant())
+
+	_, err = d.Inverse()
+	is.Error(err)
+	_, err = d.Solve([]float64{1, 2, 3, 4})
+	is.Error(err)
+}
+
+func TestSolve(t *testing.T) {
+	is := assert.New(t)
+
+	// 2x + y - z = 8, -3x - y + 2z = -11, -2x + y + 2z = -3
+	a := Matrix{
+		{2, 1, -1},
+		{-3, -1, 2},
+		{-2, 1, 2},
+	}
+	d, err := a.Decompose()
+	is.NoError(err)
+
+	x, err := d.Solve([]float64{8, -11, -3})
+	is.NoError(err)
+	is.True(float.Equal(2, x[0]))
+	is.True(float.Equal(3, x[1]))
+	is.True(float.Equal(-1, x[2]))
+
+	_, err = d.Solve([]float64{1, 2})
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestLUInverse(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{-5, 2, 6, -8},
+		{1, -5, 1, 8},
+		{7, 7, -6, -7},
+		{1, -3, 7, 4},
+	}
+	expected, err := a.Inverse()
+	is.NoError(err)
+
+	d, err := a.Decompose()
+	is.NoError(err)
+	inv, err := d.Inverse()
+	is.NoError(err)
+	is.True(inv.Equal(expected))
+}
+
+func TestLargeMatrix(t *testing.T) {
+	is := assert.New(t)
+
+	// a 6x6 transform, as two independent 3x3 blocks
+	a := Matrix{
+		{2, 0, 1, 0, 0, 0},
+		{1, 3, 2, 0, 0, 0},
+		{1, 1, 2, 0, 0, 0},
+		{0, 0, 0, 4, 1, 0},
+		{0, 0, 0, 2, 5, 1},
+		{0, 0, 0, 0, 1, 3},
+	}
+	// the determinant of a block diagonal matrix
+	// is the product of the determinants of its blocks
+	is.True(float.Equal(6*50, a.Determinant()))
+	is.True(a.Invertible())
+
+	inv, err := a.Inverse()
+	is.NoError(err)
+	identity := NewMatrix(6, 6)
+	for i := range identity {
+		identity[i][i] = 1
+	}
+	is.True(a.Multiply(inv).Equal(identity))
+
+	a[5] = []float64{0, 0, 0, 0, 0, 0}
+	is.False(a.Invertible())
+	_, err = a.Inverse()
+	is.Error(err)
+}
+
+func TestNearSingularInverse(t *testing.T) {
+	is := assert.New(t)
+
+	// a very flat but valid scaling, whose determinant is below the
+	// tolerance of float.Equal but which LU decomposition can still invert
+	s := 1e-4
+	a := Matrix{
+		{s, 0, 0, 0, 0},
+		{0, s, 0, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 1, 0},
+		{0, 0, 0, 0, 1},
+	}
+	d, err := a.Decompose()
+	is.NoError(err)
+	is.False(d.Singular())
+	inv, err := d.Inverse()
+	is.NoError(err)
+	is.True(math.Abs(inv[0][0]-1/s) < 1e-6)
+}
+
+func TestSmallScaleInverse(t *testing.T) {
+	is := assert.New(t)
+
+	// a 4x4 transform with a determinant of only 1e-6
+	a := Scaling(0.01, 0.01, 0.01)
+	is.True(a.Invertible())
+	inv, err := a.Inverse()
+	is.NoError(err)
+	is.True(inv.Equal(Scaling(100, 100, 100)))
+
+	// Invertible always agrees with Inverse, for every size
+	matrices := []Matrix{
+		a,
+		Scaling(1, 0, 1).RotateX(1),
+		{{1e-14, 0}, {0, 1e-14}},
+		{{1, 2}, {2, 4}},
+		{
+			{1e-4, 0, 0, 0, 0},
+			{0, 1, 2, 0, 0},
+			{0, 2, 4, 0, 0},
+			{0, 0, 0, 1, 0},
+			{0, 0, 0, 0, 1},
+		},
+	}
+	for _, m := range matrices {
+		_, err := m.Inverse()
+		is.Equal(err == nil, m.Invertible(), "%v", m)
+	}
+}
+
+func TestInverse2x2(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{4, 7},
+		{2, 6},
+	}
+	inv, err := a.Inverse()
+	is.NoError(err)
+	is.True(inv.Equal(Matrix{
+		{0.6, -0.7},
+		{-0.2, 0.4},
+	}))
+}
+
+func BenchmarkDeterminantCofactor(b *testing.B) {
+	m := Matrix{
+		{-2, -8, 3, 5},
+		{-3, 1, 7, 3},
+		{1, 2, -9, 6},
+		{-6, 7, 7, -9},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Determinant()
+	}
+}
+
+func BenchmarkDeterminantLU(b *testing.B) {
+	m := Matrix{
+		{-2, -8, 3, 5},
+		{-3, 1, 7, 3},
+		{1, 2, -9, 6},
+		{-6, 7, 7, -9},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d, _ := m.Decompose()
+		d.Determinant()
+	}
+}
@@ -0,0 +1,206 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Mat4 is a fixed size 4x4 matrix.
+// Unlike Matrix it is a value type, so none of its
+// operations allocate, which makes it the better fit for
+// transforming rays and points in the hot paths of a render.
+type Mat4 [4][4]float64
+
+// Identity4 returns the 4x4 identity matrix
+func Identity4() Mat4 {
+	return Mat4{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// ToMat4 converts a 4x4 Matrix into a Mat4
+func (m Matrix) ToMat4() (Mat4, error) {
+	var m4 Mat4
+	if err := m.validate(); err != nil {
+		return m4, err
+	}
+	if m.rows() != 4 || m.cols() != 4 {
+		return m4, fmt.Errorf("cannot convert a %s matrix to a Mat4: %w", m.shape(), ErrDimension)
+	}
+	for r := range m4 {
+		copy(m4[r][:], m[r])
+	}
+	return m4, nil
+}
+
+// ToMatrix converts a Mat4 into a Matrix
+func (m Mat4) ToMatrix() Matrix {
+	mat := NewMatrix(4, 4)
+	for r := range m {
+		copy(mat[r], m[r][:])
+	}
+	return mat
+}
+
+// Equal will compare two instances and return true if they are the same
+func (m Mat4) Equal(m2 Mat4) bool {
+	return m.EqualWithin(m2, float.Default)
+}
+
+// EqualWithin compares two matrices using the given comparator
+func (m Mat4) EqualWithin(m2 Mat4, cmp float.Comparator) bool {
+	for r := range m {
+		for c := range m[r] {
+			if !cmp.Equal(m[r][c], m2[r][c]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Multiply will take two matrices and multiply them
+func (m Mat4) Multiply(m2 Mat4) Mat4 {
+	var mat Mat4
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			mat[r][c] = m[r][0]*m2[0][c] +
+				m[r][1]*m2[1][c] +
+				m[r][2]*m2[2][c] +
+				m[r][3]*m2[3][c]
+		}
+	}
+	return mat
+}
+
+// MultiplyTuple multiplies the matrix by a tuple
+func (m Mat4) MultiplyTuple(t tuple.Tuple) tuple.Tuple {
+	return tuple.Tuple{
+		X: m[0][0]*t.X + m[0][1]*t.Y + m[0][2]*t.Z + m[0][3]*t.W,
+		Y: m[1][0]*t.X + m[1][1]*t.Y + m[1][2]*t.Z + m[1][3]*t.W,
+		Z: m[2][0]*t.X + m[2][1]*t.Y + m[2][2]*t.Z + m[2][3]*t.W,
+		W: m[3][0]*t.X + m[3][1]*t.Y + m[3][2]*t.Z + m[3][3]*t.W,
+	}
+}
+
+// MultiplyPoint transforms a point.
+// The matrix is assumed to be affine, so the bottom row is ignored.
+func (m Mat4) MultiplyPoint(p tuple.Point) tuple.Point {
+	return tuple.Pt(
+		m[0][0]*p.X+m[0][1]*p.Y+m[0][2]*p.Z+m[0][3],
+		m[1][0]*p.X+m[1][1]*p.Y+m[1][2]*p.Z+m[1][3],
+		m[2][0]*p.X+m[2][1]*p.Y+m[2][2]*p.Z+m[2][3],
+	)
+}
+
+// MultiplyVector transforms a vector, which unlike
+// a point is unaffected by translation.
+func (m Mat4) MultiplyVector(v tuple.Vector) tuple.Vector {
+	return tuple.Vec(
+		m[0][0]*v.X+m[0][1]*v.Y+m[0][2]*v.Z,
+		m[1][0]*v.X+m[1][1]*v.Y+m[1][2]*v.Z,
+		m[2][0]*v.X+m[2][1]*v.Y+m[2][2]*v.Z,
+	)
+}
+
+// Transpose returns the transpose of the current Mat4
+func (m Mat4) Transpose() Mat4 {
+	var mat Mat4
+	for r := range m {
+		for c := range m[r] {
+			mat[c][r] = m[r][c]
+		}
+	}
+	return mat
+}
+
+// Determinant returns the determinant of the matrix
+func (m Mat4) Determinant() float64 {
+	s, c := m.subDeterminants()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+// Invertible uses the determinant to determine wether
+// the matrix is invertible.
+func (m Mat4) Invertible() bool {
+	return !m.singular(m.Determinant())
+}
+
+// singular reports wether the determinant is too small for the matrix
+// to be inverted. It is compared to the product of the lengths of the
+// rows, the largest the determinant could be, so that uniformly small
+// or large matrices, such as a scaling by 0.01, are still invertible.
+func (m Mat4) singular(det float64) bool {
+	bound := 1.0
+	for _, row := range m {
+		bound *= math.Sqrt(row[0]*row[0] + row[1]*row[1] + row[2]*row[2] + row[3]*row[3])
+	}
+	return math.Abs(det) <= singularTolerance*bound
+}
+
+// Inverse determines the inverse of the matrix.
+// Rather than expanding cofactors recursively, it reuses the
+// twelve 2x2 determinants from the top and bottom halves of the matrix.
+func (m Mat4) Inverse() (Mat4, error) {
+	s, c := m.subDeterminants()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if m.singular(det) {
+		return Mat4{}, fmt.Errorf("matrix %v is not invertible", m)
+	}
+	inv := 1 / det
+	return Mat4{
+		{
+			(m[1][1]*c[5] - m[1][2]*c[4] + m[1][3]*c[3]) * inv,
+			(-m[0][1]*c[5] + m[0][2]*c[4] - m[0][3]*c[3]) * inv,
+			(m[3][1]*s[5] - m[3][2]*s[4] + m[3][3]*s[3]) * inv,
+			(-m[2][1]*s[5] + m[2][2]*s[4] - m[2][3]*s[3]) * inv,
+		},
+		{
+			(-m[1][0]*c[5] + m[1][2]*c[2] - m[1][3]*c[1]) * inv,
+			(m[0][0]*c[5] - m[0][2]*c[2] + m[0][3]*c[1]) * inv,
+			(-m[3][0]*s[5] + m[3][2]*s[2] - m[3][3]*s[1]) * inv,
+			(m[2][0]*s[5] - m[2][2]*s[2] + m[2][3]*s[1]) * inv,
+		},
+		{
+			(m[1][0]*c[4] - m[1][1]*c[2] + m[1][3]*c[0]) * inv,
+			(-m[0][0]*c[4] + m[0][1]*c[2] - m[0][3]*c[0]) * inv,
+			(m[3][0]*s[4] - m[3][1]*s[2] + m[3][3]*s[0]) * inv,
+			(-m[2][0]*s[4] + m[2][1]*s[2] - m[2][3]*s[0]) * inv,
+		},
+		{
+			(-m[1][0]*c[3] + m[1][1]*c[1] - m[1][2]*c[0]) * inv,
+			(m[0][0]*c[3] - m[0][1]*c[1] + m[0][2]*c[0]) * inv,
+			(-m[3][0]*s[3] + m[3][1]*s[1] - m[3][2]*s[0]) * inv,
+			(m[2][0]*s[3] - m[2][1]*s[1] + m[2][2]*s[0]) * inv,
+		},
+	}, nil
+}
+
+// subDeterminants returns the determinants of the 2x2 matrices
+// made from pairs of columns in the top two rows (s)
+// and in the bottom two rows (c).
+func (m Mat4) subDeterminants() ([6]float64, [6]float64) {
+	s := [6]float64{
+		m[0][0]*m[1][1] - m[1][0]*m[0][1],
+		m[0][0]*m[1][2] - m[1][0]*m[0][2],
+		m[0][0]*m[1][3] - m[1][0]*m[0][3],
+		m[0][1]*m[1][2] - m[1][1]*m[0][2],
+		m[0][1]*m[1][3] - m[1][1]*m[0][3],
+		m[0][2]*m[1][3] - m[1][2]*m[0][3],
+	}
+	c := [6]float64{
+		m[2][0]*m[3][1] - m[3][0]*m[2][1],
+		m[2][0]*m[3][2] - m[3][0]*m[2][2],
+		m[2][0]*m[3][3] - m[3][0]*m[2][3],
+		m[2][1]*m[3][2] - m[3][1]*m[2][2],
+		m[2][1]*m[3][3] - m[3][1]*m[2][3],
+		m[2][2]*m[3][3] - m[3][2]*m[2][3],
+	}
+	return s, c
+}
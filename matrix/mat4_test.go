@@ -0,0 +1,230 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMat4(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 8, 7, 6},
+		{5, 4, 3, 2},
+	}
+	m4, err := a.ToMat4()
+	is.NoError(err)
+	is.Equal(Mat4{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 8, 7, 6},
+		{5, 4, 3, 2},
+	}, m4)
+	is.Equal(a, m4.ToMatrix())
+
+	_, err = Matrix{{1, 2}, {3, 4}}.ToMat4()
+	is.True(errors.Is(err, ErrDimension))
+
+	_, err = Matrix{{1, 2, 3, 4}, {1, 2, 3, 4}, {1, 2}, {1, 2, 3, 4}}.ToMat4()
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestMat4Multiply(t *testing.T) {
+	is := assert.New(t)
+
+	a := Mat4{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 8, 7, 6},
+		{5, 4, 3, 2},
+	}
+	b := Mat4{
+		{-2, 1, 2, 3},
+		{3, 2, 1, -1},
+		{4, 3, 6, 5},
+		{1, 2, 7, 8},
+	}
+	expected := Mat4{
+		{20, 22, 50, 48},
+		{44, 54, 114, 108},
+		{40, 58, 110, 102},
+		{16, 26, 46, 42},
+	}
+	is.True(a.Multiply(b).Equal(expected))
+	is.Equal(a, a.Multiply(Identity4()))
+}
+
+func TestMat4MultiplyTuple(t *testing.T) {
+	is := assert.New(t)
+
+	a := Mat4{
+		{1, 2, 3, 4},
+		{2, 4, 4, 2},
+		{8, 6, 4, 1},
+		{0, 0, 0, 1},
+	}
+	res := a.MultiplyTuple(tuple.NewPoint(1, 2, 3))
+	is.True(res.Equal(tuple.NewPoint(18, 24, 33)))
+}
+
+func TestMat4MultiplyPointAndVector(t *testing.T) {
+	is := assert.New(t)
+
+	m := Identity().RotateX(math.Pi/2).Scale(5, 5, 5).Translate(10, 5, 7)
+	m4, _ := m.ToMat4()
+
+	is.True(m4.MultiplyPoint(tuple.Pt(1, 0, 1)).Equal(tuple.Pt(15, 0, 7)))
+	is.True(m4.MultiplyPoint(tuple.Pt(1, 2, 3)).Equal(m.MultiplyPoint(tuple.Pt(1, 2, 3))))
+	is.True(m4.MultiplyVector(tuple.Vec(1, 2, 3)).Equal(m.MultiplyVector(tuple.Vec(1, 2, 3))))
+}
+
+func TestMat4Transpose(t *testing.T) {
+	is := assert.New(t)
+
+	a := Mat4{
+		{0, 9, 3, 0},
+		{9, 8, 0, 8},
+		{1, 8, 5, 3},
+		{0, 0, 5, 8},
+	}
+	tnsps := Mat4{
+		{0, 9, 1, 0},
+		{9, 8, 8, 0},
+		{3, 0, 5, 5},
+		{0, 8, 3, 8},
+	}
+	is.Equal(tnsps, a.Transpose())
+}
+
+func TestMat4Determinant(t *testing.T) {
+	is := assert.New(t)
+
+	a := Mat4{
+		{-2, -8, 3, 5},
+		{-3, 1, 7, 3},
+		{1, 2, -9, 6},
+		{-6, 7, 7, -9},
+	}
+	is.Equal(-4071.0, a.Determinant())
+	is.True(a.Invertible())
+
+	b := Mat4{
+		{-4, 2, -2, -3},
+		{9, 6, 2, 6},
+		{0, -5, 1, -5},
+		{0, 0, 0, 0},
+	}
+	is.Equal(0.0, b.Determinant())
+	is.False(b.Invertible())
+	_, err := b.Inverse()
+	is.Error(err)
+}
+
+func TestMat4InverseSmallScale(t *testing.T) {
+	is := assert.New(t)
+
+	// the determinant is only 1e-6, but the matrix is far from singular
+	m, _ := Scaling(0.01, 0.01, 0.01).Translate(5, 0, 0).ToMat4()
+	is.True(m.Invertible())
+	inv, err := m.Inverse()
+	is.NoError(err)
+	is.True(m.Multiply(inv).Equal(Identity4()))
+
+	// while a matrix that flattens space is not,
+	// even when rounding leaves a tiny determinant
+	flat, _ := Scaling(1, 0, 1).RotateX(1).RotateY(0.5).Scale(3, 3, 3).ToMat4()
+	is.False(flat.Invertible())
+}
+
+func TestMat4InverseMatchesMatrix(t *testing.T) {
+	is := assert.New(t)
+
+	matrices := []Matrix{
+		{
+			{-5, 2, 6, -8},
+			{1, -5, 1, 8},
+			{7, 7, -6, -7},
+			{1, -3, 7, 4},
+		},
+		{
+			{8, -5, 9, 2},
+			{7, 5, 6, 1},
+			{-6, 0, 9, 6},
+			{-3, 0, -9, -4},
+		},
+		{
+			{9, 3, 0, 9},
+			{-5, -2, -6, -3},
+			{-4, 9, 6, 4},
+			{-7, 6, 6, 2},
+		},
+		RotationX(math.Pi / 3).Multiply(Translation(1, -2, 3)).Multiply(Scaling(2, 3, 4)),
+	}
+	for _, m := range matrices {
+		expected, err := m.Inverse()
+		is.NoError(err)
+
+		m4, err := m.ToMat4()
+		is.NoError(err)
+		inv, err := m4.Inverse()
+		is.NoError(err)
+		is.True(inv.ToMatrix().Equal(expected))
+		is.True(m4.Multiply(inv).Equal(Identity4()))
+	}
+}
+
+func TestMat4DoesNotAllocate(t *testing.T) {
+	is := assert.New(t)
+
+	m, _ := RotationY(math.Pi / 4).Multiply(Translation(0, -2, 5)).ToMat4()
+	p := tuple.NewPoint(1, 2, 3)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		inv, _ := m.Inverse()
+		p = inv.Multiply(m).MultiplyTuple(p)
+		inv.MultiplyPoint(tuple.Pt(p.X, p.Y, p.Z))
+		inv.MultiplyVector(tuple.Vec(p.X, p.Y, p.Z))
+	})
+	is.Equal(0.0, allocs)
+}
+
+func BenchmarkMatrixMultiplyTuple(b *testing.B) {
+	m := RotationY(math.Pi / 4).Multiply(Translation(0, -2, 5))
+	p := tuple.NewPoint(1, 2, 3)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.MultiplyTuple(p)
+	}
+}
+
+func BenchmarkMat4MultiplyTuple(b *testing.B) {
+	m, _ := RotationY(math.Pi / 4).Multiply(Translation(0, -2, 5)).ToMat4()
+	p := tuple.NewPoint(1, 2, 3)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.MultiplyTuple(p)
+	}
+}
+
+func BenchmarkMatrixInverse(b *testing.B) {
+	m := RotationY(math.Pi / 4).Multiply(Translation(0, -2, 5))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
+
+func BenchmarkMat4Inverse(b *testing.B) {
+	m, _ := RotationY(math.Pi / 4).Multiply(Translation(0, -2, 5)).ToMat4()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Inverse()
+	}
+}
@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
@@ -12,6 +13,16 @@ import (
 // matrix based calculations
 type Matrix [][]float64
 
+// cofactorLimit is the largest size of matrix whose determinant is
+// found by cofactor expansion. Expansion is exact for matrices of
+// integers, but grows factorially with the size, so larger matrices
+// are handled by their LU decomposition instead.
+const cofactorLimit = 4
+
+// ErrDimension is wrapped by every error caused by a
+// matrix having the wrong shape for an operation.
+var ErrDimension = errors.New("matrix dimension mismatch")
+
 // NewMatrix constructs a new matrix
 func NewMatrix(rows, columns int) Matrix {
 	mat := make(Matrix, rows)
@@ -93,12 +104,18 @@ func Shearing(xy, xx, yx, yz, zx, zy float64) Matrix {
 
 // Equal will compare two instances and return true if they are the same
 func (m Matrix) Equal(m2 Matrix) bool {
+	return m.EqualWithin(m2, float.Default)
+}
+
+// EqualWithin compares two matrices using the given comparator
+// instead of the package default
+func (m Matrix) EqualWithin(m2 Matrix, cmp float.Comparator) bool {
 	if m.rows() != m2.rows() || m.cols() != m2.cols() {
 		return false
 	}
 	for r := range m {
 		for c := range m[r] {
-			if !float.Equal(m[r][c], m2[r][c]) {
+			if !cmp.Equal(m[r][c], m2[r][c]) {
 				return false
 			}
 		}
@@ -106,12 +123,14 @@ func (m Matrix) Equal(m2 Matrix) bool {
 	return true
 }
 
-// Multiply will take two matrices and multiply them
+// Multiply will take two matrices and multiply them.
+// The number of columns in m must match the number of rows in m2,
+// use MultiplyChecked when that is not guaranteed.
 func (m Matrix) Multiply(m2 Matrix) Matrix {
 	newMat := NewMatrix(m.rows(), m2.cols())
 	for r := range m {
-		for c := range m2[r] {
-			for i := 0; i < m.rows(); i++ { // uses m.rows() as delimiter, but could also use m2.cols()
+		for c := 0; c < m2.cols(); c++ {
+			for i := 0; i < m.cols(); i++ {
 				newMat[r][c] += m[r][i] * m2[i][c]
 			}
 		}
@@ -119,6 +138,21 @@ func (m Matrix) Multiply(m2 Matrix) Matrix {
 	return newMat
 }
 
+// MultiplyChecked multiplies two matrices, returning an error
+// rather than a wrong result when their shapes do not line up.
+func (m Matrix) MultiplyChecked(m2 Matrix) (Matrix, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if err := m2.validate(); err != nil {
+		return nil, err
+	}
+	if m.cols() != m2.rows() {
+		return nil, fmt.Errorf("cannot multiply a %s matrix by a %s matrix: %w", m.shape(), m2.shape(), ErrDimension)
+	}
+	return m.Multiply(m2), nil
+}
+
 // MultiplyTuple multiplies the matrix by a tuple
 func (m Matrix) MultiplyTuple(v tuple.Tuple) tuple.Tuple {
 	colMat := NewMatrix(4, 1)
@@ -137,6 +171,32 @@ func (m Matrix) MultiplyTuple(v tuple.Tuple) tuple.Tuple {
 	}
 }
 
+// MultiplyPoint multiplies the matrix by a point.
+// The matrix is assumed to be affine, so the resulting W is dropped.
+func (m Matrix) MultiplyPoint(p tuple.Point) tuple.Point {
+	t := m.MultiplyTuple(p.Tuple())
+	return tuple.Pt(t.X, t.Y, t.Z)
+}
+
+// MultiplyVector multiplies the matrix by a vector.
+// The matrix is assumed to be affine, so the resulting W is dropped.
+func (m Matrix) MultiplyVector(v tuple.Vector) tuple.Vector {
+	t := m.MultiplyTuple(v.Tuple())
+	return tuple.Vec(t.X, t.Y, t.Z)
+}
+
+// MultiplyTupleChecked multiplies the matrix by a tuple,
+// returning an error unless the matrix has four columns.
+func (m Matrix) MultiplyTupleChecked(v tuple.Tuple) (tuple.Tuple, error) {
+	if err := m.validate(); err != nil {
+		return tuple.Tuple{}, err
+	}
+	if m.rows() != 4 || m.cols() != 4 {
+		return tuple.Tuple{}, fmt.Errorf("cannot multiply a %s matrix by a tuple: %w", m.shape(), ErrDimension)
+	}
+	return m.MultiplyTuple(v), nil
+}
+
 // Transpose returns the transpose of the current Matrix
 func (m Matrix) Transpose() Matrix {
 	mat := NewMatrix(m.cols(), m.rows())
@@ -148,13 +208,22 @@ func (m Matrix) Transpose() Matrix {
 	return mat
 }
 
-// Determinant returns the determinant of either
-// a 2x2 submatrix, or a larger one. The calculations
-// are slightly different depending on which is found.
+// Determinant returns the determinant of the matrix.
+// 1x1 and 2x2 matrices are calculated directly, matrices up to
+// 4x4 by cofactor expansion, and anything larger by LU decomposition.
 func (m Matrix) Determinant() float64 {
+	if m.rows() == 1 && m.cols() == 1 {
+		return m[0][0]
+	}
 	if m.rows() == 2 && m.cols() == 2 {
 		return (m[0][0] * m[1][1]) - (m[0][1] * m[1][0])
 	}
+	if m.rows() > cofactorLimit && m.rows() == m.cols() {
+		lu, err := m.Decompose()
+		if err == nil {
+			return lu.Determinant()
+		}
+	}
 	det := 0.0
 	if len(m) > 0 {
 		for i, val := range m[0] {
@@ -164,6 +233,15 @@ func (m Matrix) Determinant() float64 {
 	return det
 }
 
+// DeterminantChecked returns the determinant,
+// or an error if the matrix is not square.
+func (m Matrix) DeterminantChecked() (float64, error) {
+	if err := m.validateSquare("take the determinant of"); err != nil {
+		return 0, err
+	}
+	return m.Determinant(), nil
+}
+
 // Submatrix returns a new matrix with
 // a given column and row sliced off the original matrix
 func (m Matrix) Submatrix(row, col int) Matrix {
@@ -178,6 +256,18 @@ func (m Matrix) Submatrix(row, col int) Matrix {
 	return mat
 }
 
+// SubmatrixChecked returns the submatrix,
+// or an error if the row or column are out of range.
+func (m Matrix) SubmatrixChecked(row, col int) (Matrix, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	if row < 0 || row >= m.rows() || col < 0 || col >= m.cols() {
+		return nil, fmt.Errorf("row %d, column %d is out of range for a %s matrix: %w", row, col, m.shape(), ErrDimension)
+	}
+	return m.Submatrix(row, col), nil
+}
+
 // Minor calculates the determinant of a submatrix
 func (m Matrix) Minor(row, col int) float64 {
 	return m.Submatrix(row, col).Determinant()
@@ -193,29 +283,27 @@ func (m Matrix) Cofactor(row, col int) float64 {
 	return -min
 }
 
-// Invertible uses the determinant to determine wether
-// the matrix is invertible.
+// Invertible uses the pivots of the LU decomposition to determine
+// wether the matrix is invertible, exactly as Inverse does.
 func (m Matrix) Invertible() bool {
-	if float.Equal(0.0, m.Determinant()) {
-		return false
-	}
-	return true
+	lu, err := m.Decompose()
+	return err == nil && !lu.Singular()
 }
 
-// Inverse determines the inverse of a matrix
+// Inverse determines the inverse of a matrix by its LU decomposition,
+// whatever its size.
 func (m Matrix) Inverse() (Matrix, error) {
-	if !m.Invertible() {
-		return Matrix{}, fmt.Errorf("matrix %#v is not invertible", m)
+	if err := m.validateSquare("invert"); err != nil {
+		return Matrix{}, err
 	}
-	m2 := NewMatrix(m.rows(), m.cols())
-	det := m.Determinant()
-	for r := range m {
-		for c := range m[r] {
-			cof := m.Cofactor(r, c)
-			m2[c][r] = cof / det
-		}
+	lu, err := m.Decompose()
+	if err != nil {
+		return Matrix{}, err
+	}
+	if lu.Singular() {
+		return Matrix{}, fmt.Errorf("matrix %#v is not invertible", m)
 	}
-	return m2, nil
+	return lu.Inverse()
 }
 
 func (m Matrix) rows() int {
@@ -223,5 +311,33 @@ func (m Matrix) rows() int {
 }
 
 func (m Matrix) cols() int {
+	if len(m) == 0 {
+		return 0
+	}
 	return len(m[0])
 }
+
+func (m Matrix) shape() string {
+	return fmt.Sprintf("%dx%d", m.rows(), m.cols())
+}
+
+// validate returns an error if the rows of the matrix differ in length
+func (m Matrix) validate() error {
+	for r := range m {
+		if len(m[r]) != m.cols() {
+			return fmt.Errorf("row %d has %d columns but row 0 has %d: %w", r, len(m[r]), m.cols(), ErrDimension)
+		}
+	}
+	return nil
+}
+
+// validateSquare returns an error unless the matrix is square and not empty
+func (m Matrix) validateSquare(op string) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	if m.rows() == 0 || m.rows() != m.cols() {
+		return fmt.Errorf("cannot %s a %s matrix: %w", op, m.shape(), ErrDimension)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package matrix
 
 import (
+	"errors"
 	"math"
 	"testing"
 
+	"github.com/muzfuz/raytrace/float"
 	"github.com/muzfuz/raytrace/tuple"
 
 	"github.com/stretchr/testify/assert"
@@ -76,6 +78,25 @@ func TestEqual(t *testing.T) {
 	is.False(a.Equal(c))
 }
 
+func TestEqualWithin(t *testing.T) {
+	is := assert.New(t)
+
+	// large scene coordinates are further apart than the default tolerance
+	a := Translation(1e9, 0, 0)
+	b := Translation(1e9+0.01, 0, 0)
+	tol := float.Tolerance{Abs: 0.00001, Rel: 1e-9}
+
+	is.False(a.Equal(b))
+	is.True(a.EqualWithin(b, tol))
+	is.False(a.EqualWithin(Identity(), tol))
+	is.False(a.EqualWithin(Matrix{{1}}, tol))
+
+	a4, _ := a.ToMat4()
+	b4, _ := b.ToMat4()
+	is.False(a4.Equal(b4))
+	is.True(a4.EqualWithin(b4, tol))
+}
+
 func TestMultiply(t *testing.T) {
 	is := assert.New(t)
 
@@ -119,6 +140,18 @@ func TestMultiplyTuple(t *testing.T) {
 	is.True(res.Equal(expected))
 }
 
+func TestMultiplyPointAndVector(t *testing.T) {
+	is := assert.New(t)
+
+	transform := Translation(5.0, -3.0, 2.0)
+
+	// translation moves points
+	is.Equal(tuple.Pt(2.0, 1.0, 7.0), transform.MultiplyPoint(tuple.Pt(-3.0, 4.0, 5.0)))
+
+	// but does not affect vectors
+	is.Equal(tuple.Vec(-3.0, 4.0, 5.0), transform.MultiplyVector(tuple.Vec(-3.0, 4.0, 5.0)))
+}
+
 func TestIdentityMatrix(t *testing.T) {
 	is := assert.New(t)
 
@@ -319,11 +352,41 @@ func TestInverse(t *testing.T) {
 
 	b, err := a.Inverse()
 	is.NoError(err)
-	is.Equal(-160.0/532.0, b[3][2])
-	is.Equal(105.0/532.0, b[2][3])
+	is.True(float.Equal(-160.0/532.0, b[3][2]))
+	is.True(float.Equal(105.0/532.0, b[2][3]))
 	is.True(b.Equal(e))
 }
 
+func TestInverse1x1(t *testing.T) {
+	is := assert.New(t)
+
+	b, err := Matrix{{2}}.Inverse()
+	is.NoError(err)
+	is.True(b.Equal(Matrix{{0.5}}))
+
+	_, err = Matrix{{0}}.Inverse()
+	is.Error(err)
+}
+
+func TestInverse3x3(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{2, 0, 1},
+		{1, 1, 0},
+		{0, 3, 1},
+	}
+	e := Matrix{
+		{0.2, 0.6, -0.2},
+		{-0.2, 0.4, 0.2},
+		{0.6, -1.2, 0.4},
+	}
+	b, err := a.Inverse()
+	is.NoError(err)
+	is.True(b.Equal(e))
+	is.True(a.Multiply(b).Equal(Matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}))
+}
+
 func TestInverseAnother(t *testing.T) {
 	is := assert.New(t)
 
@@ -609,3 +672,113 @@ func TestChainedTransformations(t *testing.T) {
 	res := tr.MultiplyTuple(p)
 	is.Equal(e, res)
 }
+
+func TestMultiplyNonSquare(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	b := Matrix{
+		{7, 8},
+		{9, 10},
+		{11, 12},
+	}
+	expected := Matrix{
+		{58, 64},
+		{139, 154},
+	}
+	is.Equal(expected, a.Multiply(b))
+
+	res, err := a.MultiplyChecked(b)
+	is.NoError(err)
+	is.Equal(expected, res)
+}
+
+func TestMultiplyChecked(t *testing.T) {
+	is := assert.New(t)
+
+	a := Matrix{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	_, err := a.MultiplyChecked(a)
+	is.True(errors.Is(err, ErrDimension))
+	is.EqualError(err, "cannot multiply a 2x3 matrix by a 2x3 matrix: matrix dimension mismatch")
+
+	ragged := Matrix{
+		{1, 2},
+		{3},
+	}
+	_, err = a.MultiplyChecked(ragged)
+	is.True(errors.Is(err, ErrDimension))
+	is.EqualError(err, "row 1 has 1 columns but row 0 has 2: matrix dimension mismatch")
+	_, err = ragged.MultiplyChecked(a)
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestMultiplyTupleChecked(t *testing.T) {
+	is := assert.New(t)
+
+	res, err := Translation(1, 2, 3).MultiplyTupleChecked(tuple.NewPoint(0, 0, 0))
+	is.NoError(err)
+	is.Equal(tuple.NewPoint(1, 2, 3), res)
+
+	_, err = Matrix{{1, 2}, {3, 4}}.MultiplyTupleChecked(tuple.NewPoint(0, 0, 0))
+	is.True(errors.Is(err, ErrDimension))
+	is.EqualError(err, "cannot multiply a 2x2 matrix by a tuple: matrix dimension mismatch")
+}
+
+func TestDeterminantChecked(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := Matrix{{1, 5}, {-3, 2}}.DeterminantChecked()
+	is.NoError(err)
+	is.Equal(17.0, d)
+
+	_, err = Matrix{{1, 2, 3}, {4, 5, 6}}.DeterminantChecked()
+	is.True(errors.Is(err, ErrDimension))
+	is.EqualError(err, "cannot take the determinant of a 2x3 matrix: matrix dimension mismatch")
+
+	_, err = Matrix{}.DeterminantChecked()
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestSubmatrixChecked(t *testing.T) {
+	is := assert.New(t)
+
+	m := Matrix{
+		{1, 5, 0},
+		{-3, 2, 7},
+		{0, 6, -3},
+	}
+	res, err := m.SubmatrixChecked(0, 2)
+	is.NoError(err)
+	is.Equal(Matrix{{-3, 2}, {0, 6}}, res)
+
+	_, err = m.SubmatrixChecked(3, 0)
+	is.True(errors.Is(err, ErrDimension))
+	is.EqualError(err, "row 3, column 0 is out of range for a 3x3 matrix: matrix dimension mismatch")
+	_, err = m.SubmatrixChecked(0, -1)
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestInverseNonSquare(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := Matrix{{1, 2, 3}, {4, 5, 6}}.Inverse()
+	is.True(errors.Is(err, ErrDimension))
+
+	_, err = Matrix{}.Inverse()
+	is.True(errors.Is(err, ErrDimension))
+}
+
+func TestEmptyMatrix(t *testing.T) {
+	is := assert.New(t)
+
+	empty := Matrix{}
+	is.Equal(0, empty.cols())
+	is.True(empty.Equal(Matrix{}))
+	is.Equal(Matrix{}, empty.Transpose())
+}
@@ -0,0 +1,45 @@
+package matrix
+
+// The methods in this file allow transformations to be chained in the order
+// they are applied, rather than multiplied by hand in reverse:
+//
+//	Identity().RotateX(math.Pi / 2).Scale(5, 5, 5).Translate(10, 5, 7)
+//
+// is the same as
+//
+//	Translation(10, 5, 7).Multiply(Scaling(5, 5, 5)).Multiply(RotationX(math.Pi / 2))
+
+// Translate applies a translation after the current transformation
+func (m Matrix) Translate(x, y, z float64) Matrix {
+	return Translation(x, y, z).Multiply(m)
+}
+
+// Scale applies a scaling after the current transformation
+func (m Matrix) Scale(x, y, z float64) Matrix {
+	return Scaling(x, y, z).Multiply(m)
+}
+
+// RotateX applies a rotation around the X axis after the current transformation
+func (m Matrix) RotateX(r float64) Matrix {
+	return RotationX(r).Multiply(m)
+}
+
+// RotateY applies a rotation around the Y axis after the current transformation
+func (m Matrix) RotateY(r float64) Matrix {
+	return RotationY(r).Multiply(m)
+}
+
+// RotateZ applies a rotation around the Z axis after the current transformation
+func (m Matrix) RotateZ(r float64) Matrix {
+	return RotationZ(r).Multiply(m)
+}
+
+// Shear applies a shearing after the current transformation
+func (m Matrix) Shear(xy, xz, yx, yz, zx, zy float64) Matrix {
+	return Shearing(xy, xz, yx, yz, zx, zy).Multiply(m)
+}
+
+// Then applies an arbitrary transformation after the current one
+func (m Matrix) Then(m2 Matrix) Matrix {
+	return m2.Multiply(m)
+}
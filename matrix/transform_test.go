@@ -0,0 +1,63 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFluentTransformations(t *testing.T) {
+	is := assert.New(t)
+
+	p := tuple.NewPoint(1.0, 0.0, 1.0)
+
+	tr := Identity().
+		RotateX(math.Pi/2.0).
+		Scale(5.0, 5.0, 5.0).
+		Translate(10.0, 5.0, 7.0)
+
+	e := tuple.NewPoint(15.0, 0.0, 7.0)
+	res := tr.MultiplyTuple(p)
+	is.True(res.Equal(e))
+
+	// the same as multiplying by hand in reverse
+	manual := Translation(10.0, 5.0, 7.0).Multiply(Scaling(5.0, 5.0, 5.0)).Multiply(RotationX(math.Pi / 2.0))
+	is.True(tr.Equal(manual))
+}
+
+func TestFluentTransformationsEachStep(t *testing.T) {
+	is := assert.New(t)
+
+	p := tuple.NewPoint(0, 1, 0)
+
+	is.True(Identity().RotateX(math.Pi / 2).MultiplyTuple(p).Equal(tuple.NewPoint(0, 0, 1)))
+	is.True(Identity().RotateY(math.Pi / 2).MultiplyTuple(tuple.NewPoint(0, 0, 1)).Equal(tuple.NewPoint(1, 0, 0)))
+	is.True(Identity().RotateZ(math.Pi / 2).MultiplyTuple(p).Equal(tuple.NewPoint(-1, 0, 0)))
+	is.True(Identity().Shear(0, 0, 0, 0, 0, 1).MultiplyTuple(tuple.NewPoint(2, 3, 4)).Equal(tuple.NewPoint(2, 3, 7)))
+	is.True(Identity().Then(Translation(1, 2, 3)).MultiplyTuple(p).Equal(tuple.NewPoint(1, 3, 3)))
+}
+
+func TestFluentTransformationsOrderMatters(t *testing.T) {
+	is := assert.New(t)
+
+	p := tuple.NewPoint(1, 0, 0)
+
+	// translate first, then scale the translation too
+	a := Identity().Translate(1, 0, 0).Scale(2, 2, 2).MultiplyTuple(p)
+	is.True(a.Equal(tuple.NewPoint(4, 0, 0)))
+
+	// scale first, then translate
+	b := Identity().Scale(2, 2, 2).Translate(1, 0, 0).MultiplyTuple(p)
+	is.True(b.Equal(tuple.NewPoint(3, 0, 0)))
+}
+
+func TestFluentTransformationsLeaveOriginalUntouched(t *testing.T) {
+	is := assert.New(t)
+
+	base := Identity().Translate(1, 2, 3)
+	base.Scale(2, 2, 2)
+	is.Equal(Translation(1, 2, 3), base)
+}
@@ -0,0 +1,235 @@
+package pathtracer
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/muzfuz/raytrace/camera"
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/environment"
+	"github.com/muzfuz/raytrace/light"
+	"github.com/muzfuz/raytrace/material"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// bias is how far bounce and shadow rays start off the surface,
+// so that they do not hit the surface they are leaving.
+const bias = 0.0001
+
+// maxSurvival caps the chance of a path surviving Russian roulette,
+// so that paths between perfectly white surfaces still end.
+const maxSurvival = 0.95
+
+// Hit is where a ray struck the closest surface along it.
+// Normal must be a unit vector, and Distance is measured along
+// the ray, whose direction is always a unit vector.
+// Material can be a material.Material or a material.PBR.
+type Hit struct {
+	Point    tuple.Point
+	Normal   tuple.Vector
+	Distance float64
+	Material material.Surface
+}
+
+// IntersectFunc finds the closest surface in front of the ray,
+// or returns false if the ray escapes the scene. Moving surfaces
+// should be placed where they were at the ray's Time, and every
+// ray spawned along a path keeps the Time of the camera ray.
+type IntersectFunc func(r ray.Ray) (Hit, bool)
+
+// PathTracer renders global illumination by following random paths
+// of light as they scatter off the surfaces of the scene.
+// Lights are sampled directly at every bounce (next-event estimation).
+// They have no surface, so Intersect must not return hits for them.
+// Emissive materials are found by bouncing into them instead.
+// Light is physically based: the intensity of a point or spot light
+// falls off with the square of the distance, a directional light's is
+// the light falling on a surface facing it, and area and disc lights
+// give off their intensity from every point of their surface, towards
+// both of their sides.
+// Paths shorter than MinDepth always continue, longer ones are ended at
+// random by Russian roulette, which keeps the result unbiased.
+// MaxDepth is optional, when it is 0 paths can be any length.
+// Background is the environment seen by rays that miss every surface,
+// and is optional, when it is nil they are black. An environment.Sampler,
+// such as an HDRI, also lights the scene directly at every bounce, with
+// multiple importance sampling against the surface's own bounces.
+// Rand is optional, when it is nil the math/rand default source is used.
+type PathTracer struct {
+	Intersect  IntersectFunc
+	Lights     []light.Light
+	Background environment.Environment
+	MinDepth   int
+	MaxDepth   int
+	Rand       *rand.Rand
+}
+
+// New returns a PathTracer with a black background,
+// that starts Russian roulette after three bounces.
+func New(intersect IntersectFunc, lights []light.Light, seed int64) PathTracer {
+	return PathTracer{
+		Intersect:  intersect,
+		Lights:     lights,
+		Background: environment.Uniform{Color: canvas.NewColor(0, 0, 0)},
+		MinDepth:   3,
+		Rand:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Render renders the scene through the camera. Every call to Trace is
+// one random path, so the camera needs many samples per pixel, from its
+// Sampler or Adaptive settings, to get a clean image.
+func (p PathTracer) Render(ctx context.Context, c camera.Camera, progress camera.ProgressFunc) (canvas.Canvas, error) {
+	return c.Render(ctx, p.Trace, progress)
+}
+
+// Trace returns the light arriving back along the ray,
+// following a single random path through the scene.
+// It can be used as a camera.TraceFunc.
+func (p PathTracer) Trace(r ray.Ray) canvas.Color {
+	radiance := canvas.NewColor(0, 0, 0)
+	throughput := canvas.NewColor(1, 1, 1)
+	env, sampled := p.Background.(environment.Sampler)
+	// the probability of the last bounce picking the direction of r
+	pdf := 0.0
+	for depth := 0; p.MaxDepth == 0 || depth < p.MaxDepth; depth++ {
+		hit, ok := p.Intersect(r)
+		if !ok {
+			background := p.background(r.Direction)
+			if sampled && depth > 0 {
+				background = background.Scale(powerHeuristic(pdf, env.PDF(r.Direction)))
+			}
+			return radiance.Add(throughput.Multiply(background))
+		}
+		normal := hit.Normal
+		if normal.Dot(r.Direction) > 0 {
+			normal = normal.Negate()
+		}
+		over := hit.Point.Add(normal.Scale(bias))
+		wo := r.Direction.Negate()
+		m := hit.Material
+
+		radiance = radiance.Add(throughput.Multiply(m.Emission()))
+		radiance = radiance.Add(throughput.Multiply(p.direct(r, over, normal, wo, m)))
+		if sampled {
+			radiance = radiance.Add(throughput.Multiply(p.environment(r, env, over, normal, wo, m)))
+		}
+
+		wi, weight, ok := m.Sample(normal, wo, p.float(), p.float())
+		if !ok {
+			break
+		}
+		pdf = m.PDF(normal, wo, wi)
+		throughput = throughput.Multiply(weight)
+		if depth >= p.MinDepth {
+			survival := math.Min(maxComponent(throughput), maxSurvival)
+			if p.float() >= survival {
+				break
+			}
+			throughput = throughput.Scale(1 / survival)
+		}
+		r = r.Spawn(over, wi)
+	}
+	return radiance
+}
+
+// surface is a light with an area, such as a light.AreaLight
+// or a light.DiscLight, whose samples are spread evenly over it.
+type surface interface {
+	Area() float64
+	Normal() tuple.Vector
+}
+
+// direct returns the light reflected towards wo
+// that reached the point straight from every light,
+// at the moment r was cast.
+func (p PathTracer) direct(r ray.Ray, point tuple.Point, normal, wo tuple.Vector, m material.Surface) canvas.Color {
+	sum := canvas.NewColor(0, 0, 0)
+	for _, l := range p.Lights {
+		samples := l.Samples(point)
+		if len(samples) == 0 {
+			continue
+		}
+		lit := canvas.NewColor(0, 0, 0)
+		for _, s := range samples {
+			cos := s.Direction.Dot(normal)
+			if cos <= 0 || p.occluded(r, point, s.Direction, s.Distance) {
+				continue
+			}
+			f := m.BRDF(normal, wo, s.Direction)
+			lit = lit.Add(f.Multiply(s.Intensity).Scale(cos * falloff(l, s)))
+		}
+		sum = sum.Add(lit.Scale(1 / float64(len(samples))))
+	}
+	return sum
+}
+
+// environment returns the light reflected towards wo that reached the
+// point straight from one importance sampled direction of the environment.
+// It is weighted against the chance of a bounce finding the same light.
+func (p PathTracer) environment(r ray.Ray, env environment.Sampler, point tuple.Point, normal, wo tuple.Vector, m material.Surface) canvas.Color {
+	wi, pdf := env.Sample(p.float(), p.float())
+	cos := wi.Dot(normal)
+	if pdf <= 0 || cos <= 0 {
+		return canvas.NewColor(0, 0, 0)
+	}
+	if _, hit := p.Intersect(r.Spawn(point, wi)); hit {
+		return canvas.NewColor(0, 0, 0)
+	}
+	weight := powerHeuristic(pdf, m.PDF(normal, wo, wi))
+	return m.BRDF(normal, wo, wi).
+		Multiply(env.ColorAt(wi)).
+		Scale(cos * weight / pdf)
+}
+
+// falloff converts the intensity of a sample of the light into the
+// light falling on a surface facing it. A sample of a surface stands for
+// the whole of it, seen at an angle and shrunk by the distance.
+func falloff(l light.Light, s light.Sample) float64 {
+	if math.IsInf(s.Distance, 1) {
+		return 1
+	}
+	d2 := s.Distance * s.Distance
+	if lamp, ok := l.(surface); ok {
+		return lamp.Area() * math.Abs(s.Direction.Dot(lamp.Normal())) / d2
+	}
+	return 1 / d2
+}
+
+// occluded reports whether any surface lies between the point and distance
+// along the direction, at the moment r was cast.
+func (p PathTracer) occluded(r ray.Ray, point tuple.Point, direction tuple.Vector, distance float64) bool {
+	hit, ok := p.Intersect(r.Spawn(point, direction))
+	return ok && hit.Distance < distance
+}
+
+func (p PathTracer) background(direction tuple.Vector) canvas.Color {
+	if p.Background == nil {
+		return canvas.NewColor(0, 0, 0)
+	}
+	return p.Background.ColorAt(direction)
+}
+
+func (p PathTracer) float() float64 {
+	if p.Rand == nil {
+		return rand.Float64()
+	}
+	return p.Rand.Float64()
+}
+
+// powerHeuristic weighs a sample taken with probability pdf against
+// another way of sampling that would have picked it with probability other.
+// Together the weights of both add up to 1.0.
+func powerHeuristic(pdf, other float64) float64 {
+	a, b := pdf*pdf, other*other
+	if a+b == 0 {
+		return 0
+	}
+	return a / (a + b)
+}
+
+func maxComponent(c canvas.Color) float64 {
+	return math.Max(c.R(), math.Max(c.G(), c.B()))
+}
@@ -0,0 +1,314 @@
+package pathtracer
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/camera"
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/environment"
+	"github.com/muzfuz/raytrace/light"
+	"github.com/muzfuz/raytrace/material"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/ray"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// plane is a horizontal plane at height y, for building test scenes
+type plane struct {
+	y        float64
+	material material.Surface
+}
+
+// planes intersects a ray with every plane and returns the closest hit
+func planes(ps ...plane) IntersectFunc {
+	return func(r ray.Ray) (Hit, bool) {
+		var closest Hit
+		found := false
+		for _, p := range ps {
+			if r.Direction.Y == 0 {
+				continue
+			}
+			d := (p.y - r.Origin.Y) / r.Direction.Y
+			if d <= 0 || (found && d >= closest.Distance) {
+				continue
+			}
+			closest = Hit{
+				Point:    r.Position(d),
+				Normal:   tuple.Vec(0, 1, 0),
+				Distance: d,
+				Material: p.material,
+			}
+			found = true
+		}
+		return closest, found
+	}
+}
+
+func matte(c canvas.Color, diffuse float64) material.Material {
+	m := material.New()
+	m.Color = c
+	m.Diffuse = diffuse
+	return m
+}
+
+func TestNew(t *testing.T) {
+	is := assert.New(t)
+
+	p := New(planes(), nil, 1)
+	is.Equal(environment.Uniform{Color: canvas.NewColor(0, 0, 0)}, p.Background)
+	is.Equal(3, p.MinDepth)
+	is.Equal(0, p.MaxDepth)
+	is.NotNil(p.Rand)
+}
+
+func TestTraceBackground(t *testing.T) {
+	is := assert.New(t)
+
+	p := New(planes(), nil, 1)
+	p.Background = environment.Uniform{Color: canvas.NewColor(0.2, 0.4, 0.6)}
+	c := p.Trace(ray.New(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, 1)))
+	is.True(c.Equal(canvas.NewColor(0.2, 0.4, 0.6)))
+
+	p.Background = nil
+	c = p.Trace(ray.New(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, 1)))
+	is.True(c.Equal(canvas.NewColor(0, 0, 0)))
+}
+
+func TestTraceReflectsEnvironment(t *testing.T) {
+	is := assert.New(t)
+
+	// a smooth white metal floor mirrors the sky straight overhead
+	mirror, err := material.NewPBR(canvas.NewColor(1, 1, 1), 1, 0)
+	is.NoError(err)
+	p := New(planes(plane{y: 0, material: mirror}), nil, 1)
+	p.Background = environment.Sky{
+		Zenith:  canvas.NewColor(0, 0, 1),
+		Horizon: canvas.NewColor(1, 1, 1),
+		Ground:  canvas.NewColor(0, 0, 0),
+	}
+
+	c := p.Trace(ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0)))
+	is.InDelta(0.0, c.R(), 0.01)
+	is.InDelta(0.0, c.G(), 0.01)
+	is.InDelta(1.0, c.B(), 0.01)
+}
+
+func TestTraceDirectLight(t *testing.T) {
+	is := assert.New(t)
+
+	// a diffuse floor reflects its albedo over π of the light falling on it
+	floor := plane{y: 0, material: matte(canvas.NewColor(1, 0.5, 0), 0.5)}
+	albedo := canvas.NewColor(0.5, 0.25, 0).Scale(1 / math.Pi)
+
+	// 25 units of intensity five units away light the floor with 1 unit
+	l := light.NewPointLight(tuple.Pt(0, 5, 0), canvas.NewColor(25, 25, 25))
+	p := New(planes(floor), []light.Light{l}, 1)
+
+	// every bounce off the floor escapes into the black background,
+	// so only the light reaching the floor directly is seen
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+	for i := 0; i < 10; i++ {
+		is.True(p.Trace(r).Equal(albedo))
+	}
+
+	// twice as far away it is a quarter as bright
+	p.Lights = []light.Light{light.NewPointLight(tuple.Pt(0, 10, 0), canvas.NewColor(25, 25, 25))}
+	is.True(p.Trace(r).Equal(albedo.Scale(0.25)))
+
+	// light arriving at an angle is spread over more of the floor
+	p.Lights = []light.Light{light.NewDirectionalLight(tuple.Vec(-1, -1, 0), canvas.NewColor(1, 1, 1))}
+	is.True(p.Trace(r).Equal(albedo.Scale(math.Sqrt(0.5))))
+}
+
+func TestTraceAreaLight(t *testing.T) {
+	is := assert.New(t)
+
+	floor := plane{y: 0, material: matte(canvas.NewColor(1, 1, 1), 0.5)}
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+
+	// a small area light lights the floor like a point light
+	// with the intensity of all of its area together
+	area, err := light.NewAreaLight(tuple.Pt(-0.05, 5, -0.05), tuple.Vec(0.1, 0, 0), 2, tuple.Vec(0, 0, 0.1), 2, canvas.NewColor(2500, 2500, 2500))
+	is.NoError(err)
+	p := New(planes(floor), []light.Light{area}, 1)
+	is.InEpsilon(0.5/math.Pi, p.Trace(r).R(), 1e-3)
+
+	// a disc of radius 1, one unit above the floor, lights it with
+	// π * R² / (h² + R²) of its intensity
+	disc, err := light.NewDiscLight(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0), 1, 16, 16, canvas.NewColor(1, 1, 1))
+	is.NoError(err)
+	p.Lights = []light.Light{disc}
+	is.InEpsilon(0.5/math.Pi*math.Pi/2, p.Trace(r).R(), 0.01)
+}
+
+func TestTraceShadow(t *testing.T) {
+	is := assert.New(t)
+
+	floor := plane{y: 0, material: matte(canvas.NewColor(1, 1, 1), 0.5)}
+	ceiling := plane{y: 2, material: matte(canvas.NewColor(1, 1, 1), 0)}
+	l := light.NewPointLight(tuple.Pt(0, 5, 0), canvas.NewColor(1, 1, 1))
+	p := New(planes(floor, ceiling), []light.Light{l}, 1)
+
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+	is.True(p.Trace(r).Equal(canvas.NewColor(0, 0, 0)))
+}
+
+func TestTraceMovingShadow(t *testing.T) {
+	is := assert.New(t)
+
+	// the ceiling is whisked away halfway through the shot,
+	// and shadow rays must see it only while it is there
+	floor := planes(plane{y: 0, material: matte(canvas.NewColor(1, 1, 1), 0.5)})
+	both := planes(
+		plane{y: 0, material: matte(canvas.NewColor(1, 1, 1), 0.5)},
+		plane{y: 2, material: matte(canvas.NewColor(1, 1, 1), 0)},
+	)
+	scene := func(r ray.Ray) (Hit, bool) {
+		if r.Time < 0.5 {
+			return both(r)
+		}
+		return floor(r)
+	}
+	l := light.NewPointLight(tuple.Pt(0, 5, 0), canvas.NewColor(25, 25, 25))
+	p := New(scene, []light.Light{l}, 1)
+
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+	is.True(p.Trace(r).Equal(canvas.NewColor(0, 0, 0)))
+	r.Time = 0.75
+	lit := 0.5 / math.Pi
+	is.True(p.Trace(r).Equal(canvas.NewColor(lit, lit, lit)))
+}
+
+func TestTraceEmissive(t *testing.T) {
+	is := assert.New(t)
+
+	glow := matte(canvas.NewColor(0, 0, 0), 0)
+	glow.Emissive = canvas.NewColor(2, 1, 0)
+	p := New(planes(plane{y: 0, material: glow}), nil, 1)
+
+	c := p.Trace(ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0)))
+	is.True(c.Equal(canvas.NewColor(2, 1, 0)))
+}
+
+func TestTraceIndirectLight(t *testing.T) {
+	is := assert.New(t)
+
+	// a glowing ceiling lights a grey floor only by bouncing off it,
+	// and the floor reflects half of the light back up
+	ceiling := matte(canvas.NewColor(0, 0, 0), 0)
+	ceiling.Emissive = canvas.NewColor(1, 1, 1)
+	floor := plane{y: 0, material: matte(canvas.NewColor(1, 1, 1), 0.5)}
+	p := New(planes(floor, plane{y: 2, material: ceiling}), nil, 1)
+
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+	for i := 0; i < 10; i++ {
+		is.True(p.Trace(r).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	}
+}
+
+func TestTracePBR(t *testing.T) {
+	is := assert.New(t)
+
+	// a smooth metal floor mirrors the glowing ceiling, tinted by its color
+	ceiling := matte(canvas.NewColor(0, 0, 0), 0)
+	ceiling.Emissive = canvas.NewColor(1, 1, 1)
+	metal, err := material.NewPBR(canvas.NewColor(1, 0.5, 0), 1, 0)
+	is.NoError(err)
+	p := New(planes(plane{y: 0, material: metal}, plane{y: 2, material: ceiling}), nil, 1)
+
+	r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0, -1, 0))
+	c := p.Trace(r)
+	is.InDelta(1.0, c.R(), 0.01)
+	is.InDelta(0.5, c.G(), 0.01)
+	is.InDelta(0.0, c.B(), 0.01)
+}
+
+func TestTraceImageBasedLighting(t *testing.T) {
+	is := assert.New(t)
+
+	// a dim sky with a bright softbox up and to the side
+	c := canvas.NewCanvas(32, 16)
+	c.WriteAllPixels(canvas.NewColor(0.1, 0.1, 0.1))
+	for x := 10; x < 13; x++ {
+		c.WritePixel(x, 4, canvas.NewColor(20, 20, 20))
+	}
+
+	glossy, err := material.NewPBR(canvas.NewColor(0.8, 0.8, 0.8), 0.5, 0.4)
+	is.NoError(err)
+	for _, m := range []material.Surface{matte(canvas.NewColor(1, 1, 1), 0.5), glossy} {
+		floor := plane{y: 0, material: m}
+		r := ray.New(tuple.Pt(0, 1, 0), tuple.Vec(0.3, -1, 0).Normalize())
+
+		// sampling the HDRI and the surface together converges on
+		// the same light as only bouncing off the surface, with less noise
+		estimate := func(background environment.Environment) (float64, float64) {
+			p := New(planes(floor), nil, 1)
+			p.Background = background
+			const n = 100000
+			sum, squares := 0.0, 0.0
+			for i := 0; i < n; i++ {
+				v := p.Trace(r).R()
+				sum += v
+				squares += v * v
+			}
+			mean := sum / n
+			return mean, squares/n - mean*mean
+		}
+		hdri, err := environment.NewHDRI(c)
+		is.NoError(err)
+		sampled, sampledVariance := estimate(hdri)
+		bounced, bouncedVariance := estimate(environment.NewEquirectangular(c))
+
+		is.InEpsilon(bounced, sampled, 0.05)
+		is.True(sampledVariance < bouncedVariance)
+	}
+}
+
+func TestTraceFurnace(t *testing.T) {
+	is := assert.New(t)
+
+	// inside a closed glowing box that reflects half of the light it
+	// receives, the light bounces forever and converges on E / (1 - a)
+	m := matte(canvas.NewColor(1, 1, 1), 0.5)
+	m.Emissive = canvas.NewColor(1, 1, 1)
+	box := func(r ray.Ray) (Hit, bool) {
+		return Hit{
+			Point:    r.Position(1),
+			Normal:   r.Direction.Negate(),
+			Distance: 1,
+			Material: m,
+		}, true
+	}
+	p := New(box, nil, 1)
+	p.MinDepth = 0
+
+	const n = 20000
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += p.Trace(ray.New(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, 1))).R()
+	}
+	is.InDelta(2.0, sum/n, 0.05)
+
+	// capping the path length cuts the bounces short
+	p.MaxDepth = 1
+	is.True(p.Trace(ray.New(tuple.Pt(0, 0, 0), tuple.Vec(0, 0, 1))).Equal(canvas.NewColor(1, 1, 1)))
+}
+
+func TestRender(t *testing.T) {
+	is := assert.New(t)
+
+	glow := matte(canvas.NewColor(0, 0, 0), 0)
+	glow.Emissive = canvas.NewColor(0.5, 0.5, 0.5)
+	p := New(planes(plane{y: -1, material: glow}), nil, 1)
+
+	// looking straight down at the glowing floor
+	c := camera.New(4, 4, math.Pi/2)
+	c.Transform = matrix.RotationX(math.Pi / 2)
+	img, err := p.Render(context.Background(), c, nil)
+	is.NoError(err)
+	is.True(img.PixelAt(2, 2).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+}
@@ -0,0 +1,223 @@
+package quaternion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Quaternion represents a rotation in three dimensions.
+// Unlike a chain of rotations around the X, Y and Z axes it can rotate
+// around any axis directly, does not suffer from gimbal lock, and
+// can be smoothly interpolated between two orientations.
+// Only unit quaternions represent rotations.
+type Quaternion struct {
+	W float64
+	X float64
+	Y float64
+	Z float64
+}
+
+// New constructs a Quaternion from its components
+func New(w, x, y, z float64) Quaternion {
+	return Quaternion{
+		W: w,
+		X: x,
+		Y: y,
+		Z: z,
+	}
+}
+
+// Identity returns the quaternion that does not rotate at all
+func Identity() Quaternion {
+	return New(1, 0, 0, 0)
+}
+
+// FromAxisAngle returns a rotation of angle radians around the axis.
+// Like matrix.RotationX, rotations are counter-clockwise when looking
+// down the axis towards the origin.
+func FromAxisAngle(axis tuple.Vector, angle float64) (Quaternion, error) {
+	if float.Equal(0, axis.Magnitude()) {
+		return Quaternion{}, errors.New("axis must not be the zero vector")
+	}
+	a := axis.Normalize()
+	sin, cos := math.Sincos(angle / 2)
+	return New(cos, a.X*sin, a.Y*sin, a.Z*sin), nil
+}
+
+// FromEuler returns a rotation around the X axis, followed by a rotation
+// around the Y axis, followed by a rotation around the Z axis.
+// It is the same as matrix.Identity().RotateX(x).RotateY(y).RotateZ(z).
+func FromEuler(x, y, z float64) Quaternion {
+	sx, cx := math.Sincos(x / 2)
+	sy, cy := math.Sincos(y / 2)
+	sz, cz := math.Sincos(z / 2)
+	qx := New(cx, sx, 0, 0)
+	qy := New(cy, 0, sy, 0)
+	qz := New(cz, 0, 0, sz)
+	return qz.Multiply(qy).Multiply(qx)
+}
+
+// FromMatrix extracts the rotation from the upper left 3x3 of a
+// matrix. The matrix must be a pure rotation, scaling or shearing
+// will give meaningless results.
+func FromMatrix(m matrix.Matrix) (Quaternion, error) {
+	if len(m) < 3 || len(m[0]) < 3 || len(m[1]) < 3 || len(m[2]) < 3 {
+		return Quaternion{}, fmt.Errorf("cannot extract a rotation from a matrix smaller than 3x3: %w", matrix.ErrDimension)
+	}
+	// Shepperd's method, dividing by the largest of the four
+	// possible denominators to stay numerically stable.
+	var q Quaternion
+	trace := m[0][0] + m[1][1] + m[2][2]
+	switch {
+	case trace > 0:
+		s := math.Sqrt(trace+1) * 2
+		q = New(s/4, (m[2][1]-m[1][2])/s, (m[0][2]-m[2][0])/s, (m[1][0]-m[0][1])/s)
+	case m[0][0] > m[1][1] && m[0][0] > m[2][2]:
+		s := math.Sqrt(1+m[0][0]-m[1][1]-m[2][2]) * 2
+		q = New((m[2][1]-m[1][2])/s, s/4, (m[0][1]+m[1][0])/s, (m[0][2]+m[2][0])/s)
+	case m[1][1] > m[2][2]:
+		s := math.Sqrt(1+m[1][1]-m[0][0]-m[2][2]) * 2
+		q = New((m[0][2]-m[2][0])/s, (m[0][1]+m[1][0])/s, s/4, (m[1][2]+m[2][1])/s)
+	default:
+		s := math.Sqrt(1+m[2][2]-m[0][0]-m[1][1]) * 2
+		q = New((m[1][0]-m[0][1])/s, (m[0][2]+m[2][0])/s, (m[1][2]+m[2][1])/s, s/4)
+	}
+	return q.Normalize(), nil
+}
+
+// ToMatrix returns the 4x4 rotation matrix of a unit quaternion
+func (q Quaternion) ToMatrix() matrix.Matrix {
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return matrix.Matrix{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// AxisAngle returns the axis and angle, in radians, of the rotation.
+// The identity rotation has no axis and returns the X axis.
+func (q Quaternion) AxisAngle() (tuple.Vector, float64) {
+	q = q.Normalize()
+	if q.W < 0 {
+		q = q.Negate()
+	}
+	angle := 2 * math.Acos(math.Min(1, q.W))
+	s := math.Sqrt(1 - q.W*q.W)
+	if float.Equal(0, s) {
+		return tuple.Vec(1, 0, 0), 0
+	}
+	return tuple.Vec(q.X/s, q.Y/s, q.Z/s), angle
+}
+
+// Euler returns the angles that FromEuler would need to
+// recreate the rotation, in radians. The Y angle is in [-π/2, π/2].
+// At exactly ±π/2 around Y the X and Z axes line up (gimbal lock),
+// in which case the whole remaining rotation is returned around X.
+func (q Quaternion) Euler() (float64, float64, float64) {
+	m := q.Normalize().ToMatrix()
+	sinY := math.Max(-1, math.Min(1, -m[2][0]))
+	y := math.Asin(sinY)
+	if float.Equal(1, math.Abs(sinY)) {
+		return math.Atan2(-m[1][2], m[1][1]), y, 0
+	}
+	return math.Atan2(m[2][1], m[2][2]), y, math.Atan2(m[1][0], m[0][0])
+}
+
+// Equal compares the components of two quaternions.
+// Note that q and q.Negate() are different quaternions
+// that represent the same rotation.
+func (q Quaternion) Equal(q2 Quaternion) bool {
+	return float.Equal(q.W, q2.W) &&
+		float.Equal(q.X, q2.X) &&
+		float.Equal(q.Y, q2.Y) &&
+		float.Equal(q.Z, q2.Z)
+}
+
+// Multiply composes two rotations. The result rotates by q2 first
+// and then by q, the same order as multiplying their matrices.
+func (q Quaternion) Multiply(q2 Quaternion) Quaternion {
+	return New(
+		q.W*q2.W-q.X*q2.X-q.Y*q2.Y-q.Z*q2.Z,
+		q.W*q2.X+q.X*q2.W+q.Y*q2.Z-q.Z*q2.Y,
+		q.W*q2.Y-q.X*q2.Z+q.Y*q2.W+q.Z*q2.X,
+		q.W*q2.Z+q.X*q2.Y-q.Y*q2.X+q.Z*q2.W,
+	)
+}
+
+// Rotate applies the rotation to a point or vector
+func (q Quaternion) Rotate(t tuple.Tuple) tuple.Tuple {
+	p := New(0, t.X, t.Y, t.Z)
+	r := q.Multiply(p).Multiply(q.Conjugate())
+	return tuple.Tuple{X: r.X, Y: r.Y, Z: r.Z, W: t.W}
+}
+
+// Conjugate returns the quaternion with its vector part negated.
+// For a unit quaternion it is the opposite rotation.
+func (q Quaternion) Conjugate() Quaternion {
+	return New(q.W, -q.X, -q.Y, -q.Z)
+}
+
+// Negate negates every component
+func (q Quaternion) Negate() Quaternion {
+	return New(-q.W, -q.X, -q.Y, -q.Z)
+}
+
+// Dot returns the dot product of two quaternions
+func (q Quaternion) Dot(q2 Quaternion) float64 {
+	return q.W*q2.W + q.X*q2.X + q.Y*q2.Y + q.Z*q2.Z
+}
+
+// Magnitude returns the length of the quaternion
+func (q Quaternion) Magnitude() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Normalize scales the quaternion to unit length
+func (q Quaternion) Normalize() Quaternion {
+	mag := q.Magnitude()
+	return New(q.W/mag, q.X/mag, q.Y/mag, q.Z/mag)
+}
+
+// slerpThreshold is how close two rotations can be before
+// Slerp falls back to linear interpolation, to avoid
+// dividing by the sine of a tiny angle.
+const slerpThreshold = 0.9995
+
+// Slerp spherically interpolates between two rotations.
+// t of 0.0 returns a, t of 1.0 returns b, and values in between rotate
+// at a constant speed along the shortest path from a to b.
+func Slerp(a, b Quaternion, t float64) Quaternion {
+	a, b = a.Normalize(), b.Normalize()
+	dot := a.Dot(b)
+	// q and -q are the same rotation, take the shorter way round
+	if dot < 0 {
+		b = b.Negate()
+		dot = -dot
+	}
+	if dot > slerpThreshold {
+		return New(
+			a.W+t*(b.W-a.W),
+			a.X+t*(b.X-a.X),
+			a.Y+t*(b.Y-a.Y),
+			a.Z+t*(b.Z-a.Z),
+		).Normalize()
+	}
+	theta0 := math.Acos(dot)
+	theta := theta0 * t
+	sinTheta0 := math.Sin(theta0)
+	s0 := math.Cos(theta) - dot*math.Sin(theta)/sinTheta0
+	s1 := math.Sin(theta) / sinTheta0
+	return New(
+		s0*a.W+s1*b.W,
+		s0*a.X+s1*b.X,
+		s0*a.Y+s1*b.Y,
+		s0*a.Z+s1*b.Z,
+	)
+}
@@ -0,0 +1,193 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentity(t *testing.T) {
+	is := assert.New(t)
+
+	q := Identity()
+	is.Equal(New(1, 0, 0, 0), q)
+	is.Equal(matrix.Identity(), q.ToMatrix())
+
+	p := tuple.NewPoint(1, 2, 3)
+	is.True(q.Rotate(p).Equal(p))
+}
+
+func TestFromAxisAngleMatchesMatrixRotations(t *testing.T) {
+	is := assert.New(t)
+
+	angle := math.Pi / 3
+	tests := []struct {
+		axis     tuple.Vector
+		rotation matrix.Matrix
+	}{
+		{tuple.Vec(1, 0, 0), matrix.RotationX(angle)},
+		{tuple.Vec(0, 1, 0), matrix.RotationY(angle)},
+		{tuple.Vec(0, 0, 1), matrix.RotationZ(angle)},
+		{tuple.Vec(0, 0, 5), matrix.RotationZ(angle)},
+	}
+	for _, tt := range tests {
+		q, err := FromAxisAngle(tt.axis, angle)
+		is.NoError(err)
+		is.True(q.ToMatrix().Equal(tt.rotation))
+
+		p := tuple.NewPoint(1, 2, 3)
+		is.True(q.Rotate(p).Equal(tt.rotation.MultiplyTuple(p)))
+	}
+
+	_, err := FromAxisAngle(tuple.Vec(0, 0, 0), angle)
+	is.Error(err)
+}
+
+func TestRotateAroundArbitraryAxis(t *testing.T) {
+	is := assert.New(t)
+
+	// a third of a turn around the diagonal cycles the axes
+	q, err := FromAxisAngle(tuple.Vec(1, 1, 1), 2*math.Pi/3)
+	is.NoError(err)
+	is.True(q.Rotate(tuple.NewVector(1, 0, 0)).Equal(tuple.NewVector(0, 1, 0)))
+	is.True(q.Rotate(tuple.NewVector(0, 1, 0)).Equal(tuple.NewVector(0, 0, 1)))
+	is.True(q.Rotate(tuple.NewPoint(0, 0, 1)).Equal(tuple.NewPoint(1, 0, 0)))
+}
+
+func TestFromEuler(t *testing.T) {
+	is := assert.New(t)
+
+	x, y, z := 0.3, -1.2, 2.1
+	q := FromEuler(x, y, z)
+	expected := matrix.Identity().RotateX(x).RotateY(y).RotateZ(z)
+	is.True(q.ToMatrix().Equal(expected))
+}
+
+func TestEuler(t *testing.T) {
+	is := assert.New(t)
+
+	x, y, z := 0.3, -1.2, 2.1
+	ex, ey, ez := FromEuler(x, y, z).Euler()
+	is.True(float.Equal(x, ex))
+	is.True(float.Equal(y, ey))
+	is.True(float.Equal(z, ez))
+
+	// gimbal lock still recreates the same rotation
+	q := FromEuler(0.4, math.Pi/2, 0.1)
+	ex, ey, ez = q.Euler()
+	is.True(float.Equal(math.Pi/2, ey))
+	is.Equal(0.0, ez)
+	is.True(FromEuler(ex, ey, ez).ToMatrix().Equal(q.ToMatrix()))
+}
+
+func TestFromMatrix(t *testing.T) {
+	is := assert.New(t)
+
+	rotations := []matrix.Matrix{
+		matrix.Identity(),
+		matrix.RotationX(math.Pi / 4),
+		matrix.RotationY(math.Pi),
+		matrix.RotationZ(-math.Pi / 2),
+		matrix.RotationX(math.Pi),
+		matrix.RotationZ(math.Pi),
+		matrix.Identity().RotateX(0.3).RotateY(-1.2).RotateZ(2.1),
+	}
+	for _, m := range rotations {
+		q, err := FromMatrix(m)
+		is.NoError(err)
+		is.True(float.Equal(1, q.Magnitude()))
+		is.True(q.ToMatrix().Equal(m), "%v", m)
+	}
+
+	_, err := FromMatrix(matrix.Matrix{{1, 0}, {0, 1}})
+	is.True(errors.Is(err, matrix.ErrDimension))
+}
+
+func TestAxisAngle(t *testing.T) {
+	is := assert.New(t)
+
+	q, _ := FromAxisAngle(tuple.Vec(0, 2, 0), math.Pi/2)
+	axis, angle := q.AxisAngle()
+	is.True(axis.Equal(tuple.Vec(0, 1, 0)))
+	is.True(float.Equal(math.Pi/2, angle))
+
+	// the negated quaternion is the same rotation
+	axis, angle = q.Negate().AxisAngle()
+	is.True(axis.Equal(tuple.Vec(0, 1, 0)))
+	is.True(float.Equal(math.Pi/2, angle))
+
+	axis, angle = Identity().AxisAngle()
+	is.True(axis.Equal(tuple.Vec(1, 0, 0)))
+	is.Equal(0.0, angle)
+}
+
+func TestMultiplyComposesRotations(t *testing.T) {
+	is := assert.New(t)
+
+	a, _ := FromAxisAngle(tuple.Vec(1, 0, 0), math.Pi/2)
+	b, _ := FromAxisAngle(tuple.Vec(0, 1, 0), math.Pi/2)
+
+	// b after a
+	q := b.Multiply(a)
+	is.True(q.ToMatrix().Equal(b.ToMatrix().Multiply(a.ToMatrix())))
+
+	p := tuple.NewPoint(0, 1, 0)
+	is.True(q.Rotate(p).Equal(b.Rotate(a.Rotate(p))))
+	is.True(q.Rotate(p).Equal(tuple.NewPoint(1, 0, 0)))
+}
+
+func TestConjugateUndoesRotation(t *testing.T) {
+	is := assert.New(t)
+
+	q, _ := FromAxisAngle(tuple.Vec(1, 2, 3), 1.1)
+	p := tuple.NewPoint(4, -5, 6)
+	is.True(q.Conjugate().Rotate(q.Rotate(p)).Equal(p))
+	is.True(q.Multiply(q.Conjugate()).Equal(Identity()))
+}
+
+func TestSlerp(t *testing.T) {
+	is := assert.New(t)
+
+	a := Identity()
+	b, _ := FromAxisAngle(tuple.Vec(0, 0, 1), math.Pi/2)
+
+	is.True(Slerp(a, b, 0).Equal(a))
+	is.True(Slerp(a, b, 1).Equal(b))
+
+	half, _ := FromAxisAngle(tuple.Vec(0, 0, 1), math.Pi/4)
+	is.True(Slerp(a, b, 0.5).Equal(half))
+
+	// constant angular speed
+	quarter, _ := FromAxisAngle(tuple.Vec(0, 0, 1), math.Pi/8)
+	is.True(Slerp(a, b, 0.25).Equal(quarter))
+}
+
+func TestSlerpTakesShortestPath(t *testing.T) {
+	is := assert.New(t)
+
+	a := Identity()
+	b, _ := FromAxisAngle(tuple.Vec(0, 0, 1), math.Pi/2)
+
+	// -b is the same rotation as b
+	mid := Slerp(a, b.Negate(), 0.5)
+	_, angle := mid.AxisAngle()
+	is.True(float.Equal(math.Pi/4, angle))
+}
+
+func TestSlerpNearlyIdentical(t *testing.T) {
+	is := assert.New(t)
+
+	a, _ := FromAxisAngle(tuple.Vec(0, 1, 0), 0.5)
+	b, _ := FromAxisAngle(tuple.Vec(0, 1, 0), 0.5001)
+
+	mid := Slerp(a, b, 0.5)
+	is.True(float.Equal(1, mid.Magnitude()))
+	_, angle := mid.AxisAngle()
+	is.True(float.Equal(0.50005, angle))
+}
@@ -1,32 +1,48 @@
 package ray
 
 import (
-	"errors"
-
+	"github.com/muzfuz/raytrace/matrix"
 	"github.com/muzfuz/raytrace/tuple"
 )
 
-// Ray is a representation of a point in space and a direction
+// Ray is a representation of a point in space and a direction.
+// Time is the moment the ray was cast, within the camera's shutter
+// interval, so that moving objects can be placed where they were.
 type Ray struct {
-	Origin    tuple.Tuple
-	Direction tuple.Tuple
+	Origin    tuple.Point
+	Direction tuple.Vector
+	Time      float64
 }
 
 // New constructs a new Ray
-func New(origin, direction tuple.Tuple) (Ray, error) {
-	if !origin.IsPoint() {
-		return Ray{}, errors.New("origin must be a point")
-	}
-	if !direction.IsVector() {
-		return Ray{}, errors.New("direction must be a vector")
+func New(origin tuple.Point, direction tuple.Vector) Ray {
+	return Ray{
+		Origin:    origin,
+		Direction: direction,
 	}
+}
+
+// Spawn constructs a new Ray, such as a bounce or shadow ray,
+// cast at the same moment in time as r.
+func (r Ray) Spawn(origin tuple.Point, direction tuple.Vector) Ray {
 	return Ray{
 		Origin:    origin,
 		Direction: direction,
-	}, nil
+		Time:      r.Time,
+	}
 }
 
 // Position finds a points new position after traveling along a vector for t time
-func (r Ray) Position(t float64) tuple.Tuple {
+func (r Ray) Position(t float64) tuple.Point {
 	return r.Origin.Add(r.Direction.Scale(t))
 }
+
+// Transform returns a new ray with the matrix applied to both
+// its origin and direction. It does not allocate.
+func (r Ray) Transform(m matrix.Mat4) Ray {
+	return Ray{
+		Origin:    m.MultiplyPoint(r.Origin),
+		Direction: m.MultiplyVector(r.Direction),
+		Time:      r.Time,
+	}
+}
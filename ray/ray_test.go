@@ -3,6 +3,7 @@ package ray
 import (
 	"testing"
 
+	"github.com/muzfuz/raytrace/matrix"
 	"github.com/muzfuz/raytrace/tuple"
 
 	"github.com/stretchr/testify/assert"
@@ -11,24 +12,83 @@ import (
 func TestCreateAndQuery(t *testing.T) {
 	is := assert.New(t)
 
-	origin := tuple.NewPoint(1, 2, 3)
-	direction := tuple.NewVector(4, 5, 6)
-	r, _ := New(origin, direction)
+	origin := tuple.Pt(1, 2, 3)
+	direction := tuple.Vec(4, 5, 6)
+	r := New(origin, direction)
 
 	is.Equal(origin, r.Origin)
 	is.Equal(direction, r.Direction)
+	is.Equal(0.0, r.Time)
+}
+
+func TestSpawnKeepsTime(t *testing.T) {
+	is := assert.New(t)
+
+	r := New(tuple.Pt(1, 2, 3), tuple.Vec(0, 1, 0))
+	r.Time = 0.25
+
+	r2 := r.Spawn(tuple.Pt(0, 0, 0), tuple.Vec(1, 0, 0))
+	is.Equal(tuple.Pt(0, 0, 0), r2.Origin)
+	is.Equal(tuple.Vec(1, 0, 0), r2.Direction)
+	is.Equal(0.25, r2.Time)
+
+	m, _ := matrix.Translation(3, 4, 5).ToMat4()
+	is.Equal(0.25, r.Transform(m).Time)
 }
 
 func TestComputeDistanceToPoint(t *testing.T) {
 	is := assert.New(t)
 
-	r, _ := New(
-		tuple.NewPoint(2, 3, 4),
-		tuple.NewVector(1, 0, 0),
+	r := New(
+		tuple.Pt(2, 3, 4),
+		tuple.Vec(1, 0, 0),
 	)
 
-	is.Equal(tuple.NewPoint(2, 3, 4), r.Position(0))
-	is.Equal(tuple.NewPoint(3, 3, 4), r.Position(1))
-	is.Equal(tuple.NewPoint(1, 3, 4), r.Position(-1))
-	is.Equal(tuple.NewPoint(4.5, 3, 4), r.Position(2.5))
+	is.Equal(tuple.Pt(2, 3, 4), r.Position(0))
+	is.Equal(tuple.Pt(3, 3, 4), r.Position(1))
+	is.Equal(tuple.Pt(1, 3, 4), r.Position(-1))
+	is.Equal(tuple.Pt(4.5, 3, 4), r.Position(2.5))
+}
+
+func TestTranslateRay(t *testing.T) {
+	is := assert.New(t)
+
+	r := New(tuple.Pt(1, 2, 3), tuple.Vec(0, 1, 0))
+	m, _ := matrix.Translation(3, 4, 5).ToMat4()
+
+	r2 := r.Transform(m)
+	is.Equal(tuple.Pt(4, 6, 8), r2.Origin)
+	is.Equal(tuple.Vec(0, 1, 0), r2.Direction)
+}
+
+func TestScaleRay(t *testing.T) {
+	is := assert.New(t)
+
+	r := New(tuple.Pt(1, 2, 3), tuple.Vec(0, 1, 0))
+	m, _ := matrix.Scaling(2, 3, 4).ToMat4()
+
+	r2 := r.Transform(m)
+	is.Equal(tuple.Pt(2, 6, 12), r2.Origin)
+	is.Equal(tuple.Vec(0, 3, 0), r2.Direction)
+}
+
+func TestTransformDoesNotAllocate(t *testing.T) {
+	is := assert.New(t)
+
+	r := New(tuple.Pt(1, 2, 3), tuple.Vec(0, 1, 0))
+	m, _ := matrix.RotationX(1).Multiply(matrix.Translation(3, 4, 5)).ToMat4()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r = r.Transform(m)
+	})
+	is.Equal(0.0, allocs)
+}
+
+func BenchmarkTransform(b *testing.B) {
+	r := New(tuple.Pt(1, 2, 3), tuple.Vec(0, 1, 0))
+	m, _ := matrix.RotationX(1).Multiply(matrix.Translation(3, 4, 5)).ToMat4()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Transform(m)
+	}
 }
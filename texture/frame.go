@@ -0,0 +1,88 @@
+package texture
+
+import (
+	"errors"
+	"math"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// UV is a pair of texture coordinates,
+// such as those stored with the vertices of a mesh.
+type UV struct {
+	U float64
+	V float64
+}
+
+// Frame is the tangent space at a point on a surface. Tangent points
+// the way u increases on the surface, Bitangent the way v increases,
+// and Normal away from the surface. All three are unit vectors
+// at right angles to each other.
+type Frame struct {
+	Tangent   tuple.Vector
+	Bitangent tuple.Vector
+	Normal    tuple.Vector
+}
+
+// ToWorld converts a vector from tangent space,
+// with z along the normal, to world space.
+func (f Frame) ToWorld(v tuple.Vector) tuple.Vector {
+	return f.Tangent.Scale(v.X).
+		Add(f.Bitangent.Scale(v.Y)).
+		Add(f.Normal.Scale(v.Z))
+}
+
+// PlaneFrame returns the tangent space of the xz plane,
+// matching PlanarMap.
+func PlaneFrame() Frame {
+	return Frame{
+		Tangent:   tuple.Vec(1, 0, 0),
+		Bitangent: tuple.Vec(0, 0, 1),
+		Normal:    tuple.Vec(0, 1, 0),
+	}
+}
+
+// SphereFrame returns the tangent space at a point on a sphere around
+// the origin, matching SphericalMap. At the poles, where u has no
+// direction, the tangent is picked to point along +x.
+func SphereFrame(p tuple.Point) Frame {
+	n := p.Subtract(tuple.Pt(0, 0, 0)).Normalize()
+	t := tuple.Vec(-n.Z, 0, n.X)
+	if float.Equal(0, t.Magnitude()) {
+		t = tuple.Vec(1, 0, 0)
+	}
+	t = t.Normalize()
+	return Frame{
+		Tangent:   t,
+		Bitangent: t.Cross(n),
+		Normal:    n,
+	}
+}
+
+// TriangleFrame returns the tangent space of a triangle from the
+// positions and texture coordinates of its corners. For smooth shaded
+// meshes pass the interpolated vertex normal, and the tangents are
+// bent to stay at right angles to it.
+// It returns an error if the texture coordinates have no area.
+func TriangleFrame(p1, p2, p3 tuple.Point, uv1, uv2, uv3 UV, normal tuple.Vector) (Frame, error) {
+	e1, e2 := p2.Subtract(p1), p3.Subtract(p1)
+	du1, dv1 := uv2.U-uv1.U, uv2.V-uv1.V
+	du2, dv2 := uv3.U-uv1.U, uv3.V-uv1.V
+	det := du1*dv2 - du2*dv1
+	if math.Abs(det) < 1e-12 {
+		return Frame{}, errors.New("texture coordinates of the triangle have no area")
+	}
+	t := e1.Scale(dv2).Subtract(e2.Scale(dv1)).Divide(det)
+	b := e2.Scale(du1).Subtract(e1.Scale(du2)).Divide(det)
+
+	// Gram-Schmidt, in case the texture is stretched or sheared
+	n := normal.Normalize()
+	t = t.Reject(n).Normalize()
+	b = b.Reject(n).Reject(t).Normalize()
+	return Frame{
+		Tangent:   t,
+		Bitangent: b,
+		Normal:    n,
+	}, nil
+}
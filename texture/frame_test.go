@@ -0,0 +1,106 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checkOrthonormal(is *assert.Assertions, f Frame) {
+	is.True(float.Equal(1, f.Tangent.Magnitude()))
+	is.True(float.Equal(1, f.Bitangent.Magnitude()))
+	is.True(float.Equal(1, f.Normal.Magnitude()))
+	is.True(float.Equal(0, f.Tangent.Dot(f.Bitangent)))
+	is.True(float.Equal(0, f.Tangent.Dot(f.Normal)))
+	is.True(float.Equal(0, f.Bitangent.Dot(f.Normal)))
+}
+
+func TestFrameToWorld(t *testing.T) {
+	is := assert.New(t)
+
+	f := Frame{
+		Tangent:   tuple.Vec(0, 0, 1),
+		Bitangent: tuple.Vec(1, 0, 0),
+		Normal:    tuple.Vec(0, 1, 0),
+	}
+	is.Equal(tuple.Vec(3, 1, 2), f.ToWorld(tuple.Vec(2, 3, 1)))
+}
+
+func TestPlaneFrame(t *testing.T) {
+	is := assert.New(t)
+
+	f := PlaneFrame()
+	checkOrthonormal(is, f)
+
+	// the tangent and bitangent follow the texture coordinates
+	p := tuple.Pt(0.25, 0, 0.5)
+	u, v := PlanarMap(p.Add(f.Tangent.Scale(0.1)))
+	is.True(float.Equal(0.35, u))
+	is.True(float.Equal(0.5, v))
+	u, v = PlanarMap(p.Add(f.Bitangent.Scale(0.1)))
+	is.True(float.Equal(0.25, u))
+	is.True(float.Equal(0.6, v))
+}
+
+func TestSphereFrame(t *testing.T) {
+	is := assert.New(t)
+
+	for _, p := range []tuple.Point{
+		tuple.Pt(1, 0, 0),
+		tuple.Pt(0, 0, -1),
+		tuple.Pt(0.5, 0.5, 0.5),
+		tuple.Pt(-1, 2, -3),
+	} {
+		f := SphereFrame(p)
+		checkOrthonormal(is, f)
+		is.True(f.Normal.Equal(p.Subtract(tuple.Pt(0, 0, 0)).Normalize()))
+
+		// stepping along the tangent only increases u,
+		// and along the bitangent only increases v
+		n := f.Normal
+		u, v := SphericalMap(tuple.Pt(n.X, n.Y, n.Z))
+		t := n.Add(f.Tangent.Scale(0.001)).Normalize()
+		tu, tv := SphericalMap(tuple.Pt(t.X, t.Y, t.Z))
+		is.True(tu > u, "%v", p)
+		is.InDelta(v, tv, 1e-6)
+		b := n.Add(f.Bitangent.Scale(0.001)).Normalize()
+		bu, bv := SphericalMap(tuple.Pt(b.X, b.Y, b.Z))
+		is.InDelta(u, bu, 1e-6)
+		is.True(bv > v, "%v", p)
+	}
+
+	// the poles still get a frame
+	f := SphereFrame(tuple.Pt(0, 2, 0))
+	checkOrthonormal(is, f)
+	is.Equal(tuple.Vec(1, 0, 0), f.Tangent)
+}
+
+func TestTriangleFrame(t *testing.T) {
+	is := assert.New(t)
+
+	p1, p2, p3 := tuple.Pt(0, 0, 0), tuple.Pt(2, 0, 0), tuple.Pt(0, 0, 2)
+	f, err := TriangleFrame(p1, p2, p3, UV{0, 0}, UV{1, 0}, UV{0, 1}, tuple.Vec(0, 1, 0))
+	is.NoError(err)
+	is.True(f.Tangent.Equal(tuple.Vec(1, 0, 0)))
+	is.True(f.Bitangent.Equal(tuple.Vec(0, 0, 1)))
+	is.True(f.Normal.Equal(tuple.Vec(0, 1, 0)))
+
+	// the texture is mapped on rotated by a quarter turn
+	f, err = TriangleFrame(p1, p2, p3, UV{0, 0}, UV{0, 1}, UV{-1, 0}, tuple.Vec(0, 1, 0))
+	is.NoError(err)
+	is.True(f.Tangent.Equal(tuple.Vec(0, 0, -1)))
+	is.True(f.Bitangent.Equal(tuple.Vec(1, 0, 0)))
+
+	// a smooth shading normal bends the tangents with it
+	f, err = TriangleFrame(p1, p2, p3, UV{0, 0}, UV{1, 0}, UV{0, 1}, tuple.Vec(0.2, 1, 0.1))
+	is.NoError(err)
+	checkOrthonormal(is, f)
+	is.True(f.Tangent.X > 0.9)
+	is.True(f.Bitangent.Z > 0.9)
+
+	_, err = TriangleFrame(p1, p2, p3, UV{0, 0}, UV{1, 1}, UV{2, 2}, tuple.Vec(0, 1, 0))
+	is.Error(err)
+}
@@ -0,0 +1,94 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/canvas"
+)
+
+// Filter decides how an Image blends its pixels
+// when it is sampled between pixel centers.
+type Filter int
+
+// The filters an Image can use
+const (
+	// Nearest returns the color of the closest pixel
+	Nearest Filter = iota
+	// Bilinear blends the four closest pixels
+	Bilinear
+)
+
+// Wrap decides what an Image returns for
+// coordinates outside of 0.0 to 1.0.
+type Wrap int
+
+// The wrap modes an Image can use
+const (
+	// Repeat tiles the image
+	Repeat Wrap = iota
+	// Clamp stretches the pixels at the edge of the image
+	Clamp
+)
+
+// Image is a texture made from a canvas, such as a photo loaded
+// with canvas.Load. The top of the canvas is at v of 1.0.
+type Image struct {
+	Canvas canvas.Canvas
+	Filter Filter
+	Wrap   Wrap
+}
+
+// NewImage returns a repeating image texture with bilinear filtering
+func NewImage(c canvas.Canvas) Image {
+	return Image{
+		Canvas: c,
+		Filter: Bilinear,
+		Wrap:   Repeat,
+	}
+}
+
+// ColorAt samples the image at u, v
+func (img Image) ColorAt(u, v float64) canvas.Color {
+	// continuous pixel coordinates, with y flipped so that v points up
+	x := u * float64(img.Canvas.Width)
+	y := (1 - v) * float64(img.Canvas.Height)
+
+	if img.Filter == Nearest {
+		return img.pixel(int(math.Floor(x)), int(math.Floor(y)))
+	}
+
+	// pixel centers are at half pixel offsets
+	x, y = x-0.5, y-0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+	tx, ty := x-x0, y-y0
+	px, py := int(x0), int(y0)
+	top := lerp(img.pixel(px, py), img.pixel(px+1, py), tx)
+	bottom := lerp(img.pixel(px, py+1), img.pixel(px+1, py+1), tx)
+	return lerp(top, bottom, ty)
+}
+
+// pixel returns the color of a pixel, wrapping
+// coordinates that fall outside of the canvas.
+func (img Image) pixel(x, y int) canvas.Color {
+	return img.Canvas.PixelAt(
+		img.wrap(x, img.Canvas.Width),
+		img.wrap(y, img.Canvas.Height),
+	)
+}
+
+func (img Image) wrap(i, n int) int {
+	if img.Wrap == Clamp {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+	return ((i % n) + n) % n
+}
+
+func lerp(a, b canvas.Color, t float64) canvas.Color {
+	return a.Add(b.Subtract(a).Scale(t))
+}
@@ -0,0 +1,82 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checker returns a 2x2 canvas, black and white along the top row
+// and red and green along the bottom row
+func checker() canvas.Canvas {
+	c := canvas.NewCanvas(2, 2)
+	c.WritePixel(0, 0, canvas.NewColor(0, 0, 0))
+	c.WritePixel(1, 0, canvas.NewColor(1, 1, 1))
+	c.WritePixel(0, 1, canvas.NewColor(1, 0, 0))
+	c.WritePixel(1, 1, canvas.NewColor(0, 1, 0))
+	return c
+}
+
+func TestNewImage(t *testing.T) {
+	is := assert.New(t)
+
+	img := NewImage(checker())
+	is.Equal(Bilinear, img.Filter)
+	is.Equal(Repeat, img.Wrap)
+}
+
+func TestImageNearest(t *testing.T) {
+	is := assert.New(t)
+
+	img := Image{Canvas: checker(), Filter: Nearest}
+
+	// v points up, so the top of the canvas is at v of 1.0
+	is.Equal(canvas.NewColor(0, 0, 0), img.ColorAt(0.1, 0.9))
+	is.Equal(canvas.NewColor(1, 1, 1), img.ColorAt(0.9, 0.9))
+	is.Equal(canvas.NewColor(1, 0, 0), img.ColorAt(0.1, 0.1))
+	is.Equal(canvas.NewColor(0, 1, 0), img.ColorAt(0.9, 0.1))
+	is.Equal(canvas.NewColor(0, 1, 0), img.ColorAt(0.5, 0.5))
+}
+
+func TestImageBilinear(t *testing.T) {
+	is := assert.New(t)
+
+	img := Image{Canvas: checker(), Filter: Bilinear, Wrap: Clamp}
+
+	// pixel centers return the pixel's own color
+	is.True(img.ColorAt(0.25, 0.75).Equal(canvas.NewColor(0, 0, 0)))
+	is.True(img.ColorAt(0.75, 0.25).Equal(canvas.NewColor(0, 1, 0)))
+
+	// halfway between two pixels blends them evenly
+	is.True(img.ColorAt(0.5, 0.75).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	is.True(img.ColorAt(0.25, 0.5).Equal(canvas.NewColor(0.5, 0, 0)))
+
+	// the center of the image blends all four
+	is.True(img.ColorAt(0.5, 0.5).Equal(canvas.NewColor(0.5, 0.5, 0.25)))
+
+	// a quarter of the way from black to white
+	is.True(img.ColorAt(0.375, 0.75).Equal(canvas.NewColor(0.25, 0.25, 0.25)))
+}
+
+func TestImageWrap(t *testing.T) {
+	is := assert.New(t)
+
+	repeat := Image{Canvas: checker(), Filter: Nearest, Wrap: Repeat}
+	is.Equal(canvas.NewColor(0, 0, 0), repeat.ColorAt(1.1, 1.9))
+	is.Equal(canvas.NewColor(1, 1, 1), repeat.ColorAt(-0.1, 0.9))
+	is.Equal(canvas.NewColor(1, 0, 0), repeat.ColorAt(0.1, -0.9))
+
+	clamp := Image{Canvas: checker(), Filter: Nearest, Wrap: Clamp}
+	is.Equal(canvas.NewColor(1, 1, 1), clamp.ColorAt(1.1, 1.9))
+	is.Equal(canvas.NewColor(0, 0, 0), clamp.ColorAt(-0.1, 0.9))
+	is.Equal(canvas.NewColor(1, 0, 0), clamp.ColorAt(0.1, -0.9))
+
+	// bilinear filtering blends across the edge when repeating,
+	// and not when clamping
+	repeat.Filter = Bilinear
+	clamp.Filter = Bilinear
+	is.True(repeat.ColorAt(0, 0.75).Equal(canvas.NewColor(0.5, 0.5, 0.5)))
+	is.True(clamp.ColorAt(0, 0.75).Equal(canvas.NewColor(0, 0, 0)))
+}
@@ -0,0 +1,101 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Mapping turns a point on the surface of an object into u, v
+// texture coordinates, both between 0.0 and 1.0.
+type Mapping func(p tuple.Point) (float64, float64)
+
+// SphericalMap maps a unit sphere around the origin like a globe.
+// u follows the longitude, starting and ending at -z,
+// and v the latitude, from the south to the north pole.
+func SphericalMap(p tuple.Point) (float64, float64) {
+	theta := math.Atan2(p.X, p.Z)
+	radius := p.Subtract(tuple.Pt(0, 0, 0)).Magnitude()
+	phi := math.Acos(p.Y / radius)
+	u := 1 - (theta/(2*math.Pi) + 0.5)
+	v := 1 - phi/math.Pi
+	return u, v
+}
+
+// PlanarMap tiles the xz plane with the texture,
+// repeating every unit along x and z.
+func PlanarMap(p tuple.Point) (float64, float64) {
+	return mod(p.X, 1), mod(p.Z, 1)
+}
+
+// CylindricalMap wraps the texture once around a unit cylinder along
+// the y axis, repeating every unit up the cylinder.
+func CylindricalMap(p tuple.Point) (float64, float64) {
+	theta := math.Atan2(p.X, p.Z)
+	u := 1 - (theta/(2*math.Pi) + 0.5)
+	return u, mod(p.Y, 1)
+}
+
+// Face is one of the six faces of a cube
+type Face int
+
+// The faces of a cube, named from the point of view
+// of someone in front of it looking towards -z, so Front faces +z.
+const (
+	Left Face = iota
+	Front
+	Right
+	Back
+	Up
+	Down
+)
+
+// CubeMap finds which face of a cube from -1 to 1 the point is on,
+// and its texture coordinates on that face. Each face is seen from
+// the outside, with Up and Down lined up with Front.
+func CubeMap(p tuple.Point) (Face, float64, float64) {
+	face := CubeFace(p)
+	switch face {
+	case Left:
+		return face, mod(p.Z+1, 2) / 2, mod(p.Y+1, 2) / 2
+	case Front:
+		return face, mod(p.X+1, 2) / 2, mod(p.Y+1, 2) / 2
+	case Right:
+		return face, mod(1-p.Z, 2) / 2, mod(p.Y+1, 2) / 2
+	case Back:
+		return face, mod(1-p.X, 2) / 2, mod(p.Y+1, 2) / 2
+	case Up:
+		return face, mod(p.X+1, 2) / 2, mod(1-p.Z, 2) / 2
+	default:
+		return face, mod(p.X+1, 2) / 2, mod(p.Z+1, 2) / 2
+	}
+}
+
+// CubeFace returns the face of a cube that the point is closest to,
+// which is the axis along which it is furthest from the center.
+func CubeFace(p tuple.Point) Face {
+	coord := math.Max(math.Abs(p.X), math.Max(math.Abs(p.Y), math.Abs(p.Z)))
+	switch coord {
+	case p.X:
+		return Right
+	case -p.X:
+		return Left
+	case p.Y:
+		return Up
+	case -p.Y:
+		return Down
+	case p.Z:
+		return Front
+	default:
+		return Back
+	}
+}
+
+// mod is the remainder of a / b, always between 0 and b
+func mod(a, b float64) float64 {
+	m := math.Mod(a, b)
+	if m < 0 {
+		m += b
+	}
+	return m
+}
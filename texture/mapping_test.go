@@ -0,0 +1,111 @@
+package texture
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type uvTest struct {
+	point tuple.Point
+	u     float64
+	v     float64
+}
+
+func checkMapping(is *assert.Assertions, m Mapping, tests []uvTest) {
+	for _, tt := range tests {
+		u, v := m(tt.point)
+		is.True(float.Equal(tt.u, u), "u of %v is %v, want %v", tt.point, u, tt.u)
+		is.True(float.Equal(tt.v, v), "v of %v is %v, want %v", tt.point, v, tt.v)
+	}
+}
+
+func TestSphericalMap(t *testing.T) {
+	is := assert.New(t)
+
+	s2 := math.Sqrt(2) / 2
+	checkMapping(is, SphericalMap, []uvTest{
+		{tuple.Pt(0, 0, -1), 0.0, 0.5},
+		{tuple.Pt(1, 0, 0), 0.25, 0.5},
+		{tuple.Pt(0, 0, 1), 0.5, 0.5},
+		{tuple.Pt(-1, 0, 0), 0.75, 0.5},
+		{tuple.Pt(0, 1, 0), 0.5, 1.0},
+		{tuple.Pt(0, -1, 0), 0.5, 0.0},
+		{tuple.Pt(s2, s2, 0), 0.25, 0.75},
+	})
+}
+
+func TestPlanarMap(t *testing.T) {
+	is := assert.New(t)
+
+	checkMapping(is, PlanarMap, []uvTest{
+		{tuple.Pt(0.25, 0, 0.5), 0.25, 0.5},
+		{tuple.Pt(0.25, 0, -0.25), 0.25, 0.75},
+		{tuple.Pt(0.25, 0.5, -0.25), 0.25, 0.75},
+		{tuple.Pt(1.25, 0, 0.5), 0.25, 0.5},
+		{tuple.Pt(0.25, 0, -1.75), 0.25, 0.25},
+		{tuple.Pt(1, 0, -1), 0.0, 0.0},
+		{tuple.Pt(0, 0, 0), 0.0, 0.0},
+	})
+}
+
+func TestCylindricalMap(t *testing.T) {
+	is := assert.New(t)
+
+	s2 := math.Sqrt(2) / 2
+	checkMapping(is, CylindricalMap, []uvTest{
+		{tuple.Pt(0, 0, -1), 0.0, 0.0},
+		{tuple.Pt(0, 0.5, -1), 0.0, 0.5},
+		{tuple.Pt(0, 1, -1), 0.0, 0.0},
+		{tuple.Pt(s2, 0.5, -s2), 0.125, 0.5},
+		{tuple.Pt(1, 0.5, 0), 0.25, 0.5},
+		{tuple.Pt(s2, 0.5, s2), 0.375, 0.5},
+		{tuple.Pt(0, -0.25, 1), 0.5, 0.75},
+		{tuple.Pt(-s2, 0.5, s2), 0.625, 0.5},
+		{tuple.Pt(-1, 1.25, 0), 0.75, 0.25},
+		{tuple.Pt(-s2, 0.5, -s2), 0.875, 0.5},
+	})
+}
+
+func TestCubeFace(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(Left, CubeFace(tuple.Pt(-1, 0.5, -0.25)))
+	is.Equal(Right, CubeFace(tuple.Pt(1.1, -0.75, 0.8)))
+	is.Equal(Front, CubeFace(tuple.Pt(0.1, 0.6, 0.9)))
+	is.Equal(Back, CubeFace(tuple.Pt(-0.7, 0, -2)))
+	is.Equal(Up, CubeFace(tuple.Pt(0.5, 1, 0.9)))
+	is.Equal(Down, CubeFace(tuple.Pt(-0.2, -1.3, 1.1)))
+}
+
+func TestCubeMap(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		face Face
+		uvTest
+	}{
+		{Front, uvTest{tuple.Pt(-0.5, 0.5, 1), 0.25, 0.75}},
+		{Front, uvTest{tuple.Pt(0.5, -0.5, 1), 0.75, 0.25}},
+		{Back, uvTest{tuple.Pt(0.5, 0.5, -1), 0.25, 0.75}},
+		{Back, uvTest{tuple.Pt(-0.5, -0.5, -1), 0.75, 0.25}},
+		{Left, uvTest{tuple.Pt(-1, 0.5, -0.5), 0.25, 0.75}},
+		{Left, uvTest{tuple.Pt(-1, -0.5, 0.5), 0.75, 0.25}},
+		{Right, uvTest{tuple.Pt(1, 0.5, 0.5), 0.25, 0.75}},
+		{Right, uvTest{tuple.Pt(1, -0.5, -0.5), 0.75, 0.25}},
+		{Up, uvTest{tuple.Pt(-0.5, 1, -0.5), 0.25, 0.75}},
+		{Up, uvTest{tuple.Pt(0.5, 1, 0.5), 0.75, 0.25}},
+		{Down, uvTest{tuple.Pt(-0.5, -1, 0.5), 0.25, 0.75}},
+		{Down, uvTest{tuple.Pt(0.5, -1, -0.5), 0.75, 0.25}},
+	}
+	for _, tt := range tests {
+		face, u, v := CubeMap(tt.point)
+		is.Equal(tt.face, face)
+		is.True(float.Equal(tt.u, u))
+		is.True(float.Equal(tt.v, v))
+	}
+}
@@ -0,0 +1,63 @@
+package texture
+
+import (
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// NormalMap bends shading normals using an image, as baked by most
+// texturing tools. Each pixel is a tangent space normal, with red along
+// the tangent, green along the bitangent and blue along the normal, all
+// mapped from -1.0 to 1.0 onto 0.0 to 1.0. An unperturbed normal is
+// the light blue (0.5, 0.5, 1.0).
+type NormalMap struct {
+	Texture Texture
+}
+
+// Normal returns the shading normal at u, v for a surface with the
+// given tangent space.
+func (m NormalMap) Normal(f Frame, u, v float64) tuple.Vector {
+	c := m.Texture.ColorAt(u, v)
+	n := tuple.Vec(2*c.R()-1, 2*c.G()-1, 2*c.B()-1)
+	return f.ToWorld(n).Normalize()
+}
+
+// defaultDelta is how far apart, in texture coordinates,
+// BumpMap measures the height on either side of a point.
+const defaultDelta = 0.001
+
+// BumpMap bends shading normals as though the surface was raised by
+// the luminance of the Height texture, multiplied by Scale.
+// Delta is how far apart the height is measured, and
+// for an image is best set to the size of one pixel.
+type BumpMap struct {
+	Height Texture
+	Scale  float64
+	Delta  float64
+}
+
+// NewBumpMap returns a BumpMap that measures the height
+// a thousandth of the texture apart.
+func NewBumpMap(height Texture, scale float64) BumpMap {
+	return BumpMap{
+		Height: height,
+		Scale:  scale,
+		Delta:  defaultDelta,
+	}
+}
+
+// Normal returns the shading normal at u, v for a surface with the
+// given tangent space. The normal tilts away from the slope of the
+// height, like the surface of a real bump.
+func (m BumpMap) Normal(f Frame, u, v float64) tuple.Vector {
+	d := m.Delta
+	dhdu := (m.height(u+d, v) - m.height(u-d, v)) / (2 * d)
+	dhdv := (m.height(u, v+d) - m.height(u, v-d)) / (2 * d)
+	return f.Normal.
+		Subtract(f.Tangent.Scale(m.Scale * dhdu)).
+		Subtract(f.Bitangent.Scale(m.Scale * dhdv)).
+		Normalize()
+}
+
+func (m BumpMap) height(u, v float64) float64 {
+	return m.Height.ColorAt(u, v).Luminance()
+}
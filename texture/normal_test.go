@@ -0,0 +1,73 @@
+package texture
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ramp is a texture that gets brighter along u
+type ramp struct{}
+
+func (ramp) ColorAt(u, v float64) canvas.Color {
+	return canvas.NewColor(u, u, u)
+}
+
+func TestNormalMap(t *testing.T) {
+	is := assert.New(t)
+
+	f := Frame{
+		Tangent:   tuple.Vec(0, 0, 1),
+		Bitangent: tuple.Vec(1, 0, 0),
+		Normal:    tuple.Vec(0, 1, 0),
+	}
+
+	// light blue leaves the normal alone
+	m := NormalMap{Texture: solid(canvas.NewColor(0.5, 0.5, 1))}
+	is.True(m.Normal(f, 0.3, 0.3).Equal(tuple.Vec(0, 1, 0)))
+
+	// red tilts it towards the tangent, and green towards the bitangent
+	m = NormalMap{Texture: solid(canvas.NewColor(1, 0.5, 1))}
+	is.True(m.Normal(f, 0.3, 0.3).Equal(tuple.Vec(0, 1, 1).Normalize()))
+	m = NormalMap{Texture: solid(canvas.NewColor(0.5, 0, 1))}
+	is.True(m.Normal(f, 0.3, 0.3).Equal(tuple.Vec(-1, 1, 0).Normalize()))
+
+	// the map is read at u, v
+	img := Image{Canvas: canvas.NewCanvas(2, 1), Filter: Nearest}
+	img.Canvas.WritePixel(0, 0, canvas.NewColor(0.5, 0.5, 1))
+	img.Canvas.WritePixel(1, 0, canvas.NewColor(0, 0.5, 0.5))
+	m = NormalMap{Texture: img}
+	is.True(m.Normal(f, 0.25, 0.5).Equal(tuple.Vec(0, 1, 0)))
+	is.True(m.Normal(f, 0.75, 0.5).Equal(tuple.Vec(0, 0, -1)))
+}
+
+func TestNewBumpMap(t *testing.T) {
+	is := assert.New(t)
+
+	m := NewBumpMap(ramp{}, 2)
+	is.Equal(2.0, m.Scale)
+	is.Equal(0.001, m.Delta)
+}
+
+func TestBumpMap(t *testing.T) {
+	is := assert.New(t)
+
+	f := PlaneFrame()
+
+	// a flat height leaves the normal alone
+	m := NewBumpMap(solid(canvas.NewColor(0.7, 0.7, 0.7)), 1)
+	is.True(m.Normal(f, 0.5, 0.5).Equal(tuple.Vec(0, 1, 0)))
+
+	// a slope rising along u tilts the normal back against u
+	m = NewBumpMap(ramp{}, 1)
+	is.True(m.Normal(f, 0.5, 0.5).Equal(tuple.Vec(-1, 1, 0).Normalize()))
+
+	// a larger scale makes the bumps steeper
+	m.Scale = math.Sqrt(3)
+	n := m.Normal(f, 0.5, 0.5)
+	is.InDelta(math.Pi/3, n.Angle(f.Normal), 1e-6)
+}
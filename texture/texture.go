@@ -0,0 +1,69 @@
+package texture
+
+import (
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// Texture is a two-dimensional source of color, such as an image.
+// u runs from 0.0 on the left to 1.0 on the right, and
+// v from 0.0 at the bottom to 1.0 at the top.
+type Texture interface {
+	ColorAt(u, v float64) canvas.Color
+}
+
+// Pattern wraps a texture around an object, using the Mapping
+// to turn points on the object's surface into texture coordinates.
+// Points are in object space, so the pattern moves with the object.
+type Pattern struct {
+	Texture Texture
+	Mapping Mapping
+}
+
+// New returns a Pattern that wraps the texture around an object
+func New(t Texture, m Mapping) Pattern {
+	return Pattern{
+		Texture: t,
+		Mapping: m,
+	}
+}
+
+// At returns the color of the pattern at a point on the object's surface
+func (p Pattern) At(point tuple.Point) canvas.Color {
+	return p.Texture.ColorAt(p.Mapping(point))
+}
+
+// Cube textures each face of a cube separately, the same
+// way a skybox is made of six separate images.
+type Cube struct {
+	Left  Texture
+	Front Texture
+	Right Texture
+	Back  Texture
+	Up    Texture
+	Down  Texture
+}
+
+// At returns the color of the cube at a point on its surface
+func (c Cube) At(point tuple.Point) canvas.Color {
+	face, u, v := CubeMap(point)
+	return c.Face(face).ColorAt(u, v)
+}
+
+// Face returns the texture of one face of the cube
+func (c Cube) Face(f Face) Texture {
+	switch f {
+	case Left:
+		return c.Left
+	case Front:
+		return c.Front
+	case Right:
+		return c.Right
+	case Back:
+		return c.Back
+	case Up:
+		return c.Up
+	default:
+		return c.Down
+	}
+}
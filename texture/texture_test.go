@@ -0,0 +1,60 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/muzfuz/raytrace/canvas"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// solid is a texture of a single color
+type solid canvas.Color
+
+func (s solid) ColorAt(u, v float64) canvas.Color {
+	return canvas.Color(s)
+}
+
+// uv is a texture that returns its coordinates as a color
+type uv struct{}
+
+func (uv) ColorAt(u, v float64) canvas.Color {
+	return canvas.NewColor(u, v, 0)
+}
+
+func TestPattern(t *testing.T) {
+	is := assert.New(t)
+
+	p := New(uv{}, PlanarMap)
+	is.True(p.At(tuple.Pt(1.25, 7, 0.5)).Equal(canvas.NewColor(0.25, 0.5, 0)))
+
+	// an image wrapped around a sphere, with the top row at the north pole
+	img := Image{Canvas: checker(), Filter: Nearest}
+	p = New(img, SphericalMap)
+	is.Equal(canvas.NewColor(0, 0, 0), p.At(tuple.Pt(0.01, 1, -0.01)))
+	is.Equal(canvas.NewColor(1, 0, 0), p.At(tuple.Pt(0.01, -1, -0.01)))
+}
+
+func TestCube(t *testing.T) {
+	is := assert.New(t)
+
+	c := Cube{
+		Left:  solid(canvas.NewColor(1, 1, 0)),
+		Front: solid(canvas.NewColor(0, 1, 1)),
+		Right: solid(canvas.NewColor(1, 0, 0)),
+		Back:  solid(canvas.NewColor(0, 1, 0)),
+		Up:    solid(canvas.NewColor(1, 0.5, 0)),
+		Down:  solid(canvas.NewColor(1, 1, 1)),
+	}
+	is.Equal(canvas.NewColor(1, 1, 0), c.At(tuple.Pt(-1, 0, 0)))
+	is.Equal(canvas.NewColor(0, 1, 1), c.At(tuple.Pt(0, 0, 1)))
+	is.Equal(canvas.NewColor(1, 0, 0), c.At(tuple.Pt(1, 0, 0)))
+	is.Equal(canvas.NewColor(0, 1, 0), c.At(tuple.Pt(0, 0, -1)))
+	is.Equal(canvas.NewColor(1, 0.5, 0), c.At(tuple.Pt(0, 1, 0)))
+	is.Equal(canvas.NewColor(1, 1, 1), c.At(tuple.Pt(0, -1, 0)))
+
+	// the texture coordinates are those of the face
+	c.Front = uv{}
+	is.True(c.At(tuple.Pt(0.5, -0.5, 1)).Equal(canvas.NewColor(0.75, 0.25, 0)))
+}
@@ -0,0 +1,91 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/muzfuz/raytrace/matrix"
+)
+
+// Keyframe is the transformation of an object at a moment in time
+type Keyframe struct {
+	Time      float64
+	Transform matrix.Matrix
+}
+
+// Animation moves an object through a sequence of keyframes,
+// blending the transformations between them with Interpolate.
+// Before the first keyframe and after the last the object holds still.
+// The zero Animation never moves the object from the origin.
+type Animation struct {
+	times    []float64
+	matrices []matrix.Matrix
+	parts    []Decomposition
+}
+
+// Animate builds an Animation from keyframes given in any order.
+// It returns an error when there are no keyframes, when two keyframes
+// share a time, or when a transformation cannot be decomposed.
+func Animate(keyframes ...Keyframe) (Animation, error) {
+	if len(keyframes) == 0 {
+		return Animation{}, errors.New("cannot animate without keyframes")
+	}
+	sorted := append([]Keyframe(nil), keyframes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	var a Animation
+	for i, k := range sorted {
+		if i > 0 && k.Time == sorted[i-1].Time {
+			return Animation{}, fmt.Errorf("two keyframes at time %g", k.Time)
+		}
+		d, err := Decompose(k.Transform)
+		if err != nil {
+			return Animation{}, fmt.Errorf("keyframe at time %g: %w", k.Time, err)
+		}
+		a.times = append(a.times, k.Time)
+		a.matrices = append(a.matrices, k.Transform)
+		a.parts = append(a.parts, d)
+	}
+	return a, nil
+}
+
+// Linear animates an object from start at time 0 to end at time 1,
+// to match a camera shutter that is open from 0 to 1.
+func Linear(start, end matrix.Matrix) (Animation, error) {
+	return Animate(Keyframe{Time: 0, Transform: start}, Keyframe{Time: 1, Transform: end})
+}
+
+// At returns the transformation of the object at time t.
+// At a keyframe it is that keyframe's own matrix.
+func (a Animation) At(t float64) matrix.Matrix {
+	last := len(a.times) - 1
+	if last < 0 {
+		return matrix.Identity()
+	}
+	if t <= a.times[0] {
+		return a.matrices[0]
+	}
+	if t >= a.times[last] {
+		return a.matrices[last]
+	}
+	// the first keyframe after t, which has one before it
+	i := sort.SearchFloat64s(a.times, t)
+	if a.times[i] == t {
+		return a.matrices[i]
+	}
+	span := a.times[i] - a.times[i-1]
+	return Interpolate(a.parts[i-1], a.parts[i], (t-a.times[i-1])/span).Recompose()
+}
+
+// InverseAt returns the inverse of the transformation at time t, ready
+// to move a ray cast at that time into the object's space.
+func (a Animation) InverseAt(t float64) (matrix.Mat4, error) {
+	m, err := a.At(t).ToMat4()
+	if err != nil {
+		return m, err
+	}
+	return m.Inverse()
+}
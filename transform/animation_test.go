@@ -0,0 +1,107 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinear(t *testing.T) {
+	is := assert.New(t)
+
+	a, err := Linear(matrix.Translation(0, 0, 0), matrix.Translation(4, 2, 0))
+	is.NoError(err)
+	is.True(a.At(0).Equal(matrix.Identity()))
+	is.True(a.At(0.5).Equal(matrix.Translation(2, 1, 0)))
+	is.True(a.At(1).Equal(matrix.Translation(4, 2, 0)))
+
+	// outside the keyframes the object holds still
+	is.True(a.At(-1).Equal(matrix.Identity()))
+	is.True(a.At(2).Equal(matrix.Translation(4, 2, 0)))
+}
+
+func TestAnimateKeyframes(t *testing.T) {
+	is := assert.New(t)
+
+	// given out of order, a quarter turn and back again
+	a, err := Animate(
+		Keyframe{Time: 2, Transform: matrix.Identity()},
+		Keyframe{Time: 0, Transform: matrix.Identity()},
+		Keyframe{Time: 1, Transform: matrix.RotationY(math.Pi / 2)},
+	)
+	is.NoError(err)
+	is.True(a.At(0.5).Equal(matrix.RotationY(math.Pi / 4)))
+	is.True(a.At(1).Equal(matrix.RotationY(math.Pi / 2)))
+	is.True(a.At(1.5).Equal(matrix.RotationY(math.Pi / 4)))
+
+	// rotation is spherical, so points keep their distance from the axis
+	p := a.At(0.3).MultiplyPoint(tuple.Pt(0, 0, 1))
+	is.True(tuple.Vec(p.X, p.Y, p.Z).Magnitude() > 0.99999)
+}
+
+func TestAnimateAtKeyframes(t *testing.T) {
+	is := assert.New(t)
+
+	keyframes := []Keyframe{
+		{Time: 0, Transform: matrix.Identity().Scale(-1, 2, 3).Shear(0.5, 0.2, 0, 0.3, 0, 0).Translate(1, 2, 3)},
+		{Time: 0.5, Transform: matrix.Identity().RotateZ(1).Scale(0.01, 0.01, 0.01)},
+		{Time: 1, Transform: matrix.Identity().Shear(0.1, 0, 0.4, 0, 0, 0.2).Translate(-4, 0, 2)},
+	}
+	a, err := Animate(keyframes...)
+	is.NoError(err)
+	for _, k := range keyframes {
+		is.Equal(k.Transform, a.At(k.Time))
+		d, err := Decompose(k.Transform)
+		is.NoError(err)
+		is.True(d.Recompose().Equal(k.Transform))
+	}
+
+	same, err := Linear(keyframes[0].Transform, keyframes[0].Transform)
+	is.NoError(err)
+	is.True(same.At(0.5).Equal(keyframes[0].Transform))
+}
+
+func TestAnimateInverse(t *testing.T) {
+	is := assert.New(t)
+
+	a, err := Linear(matrix.Identity(), matrix.Translation(2, 0, 0).Scale(2, 2, 2))
+	is.NoError(err)
+	inv, err := a.InverseAt(0.5)
+	is.NoError(err)
+	expected, err := a.At(0.5).Inverse()
+	is.NoError(err)
+	is.True(inv.ToMatrix().Equal(expected))
+
+	var still Animation
+	is.True(still.At(0.5).Equal(matrix.Identity()))
+}
+
+func TestLinearSmallScale(t *testing.T) {
+	is := assert.New(t)
+
+	// growing from a speck to full size
+	a, err := Linear(matrix.Scaling(1e-6, 1e-6, 1e-6), matrix.Identity())
+	is.NoError(err)
+	is.InEpsilon(1e-6, a.At(0).MultiplyPoint(tuple.Pt(1, 0, 0)).X, 1e-9)
+	is.InEpsilon(0.5+0.5e-6, a.At(0.5).MultiplyPoint(tuple.Pt(1, 0, 0)).X, 1e-9)
+}
+
+func TestAnimateErrors(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := Animate()
+	is.Error(err)
+
+	_, err = Animate(
+		Keyframe{Time: 1, Transform: matrix.Identity()},
+		Keyframe{Time: 1, Transform: matrix.Translation(1, 0, 0)},
+	)
+	is.EqualError(err, "two keyframes at time 1")
+
+	_, err = Linear(matrix.Identity(), matrix.Scaling(0, 1, 1))
+	is.EqualError(err, "keyframe at time 1: cannot decompose a matrix with zero scale")
+}
@@ -0,0 +1,164 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/quaternion"
+	"github.com/muzfuz/raytrace/tuple"
+)
+
+// flatTolerance is how small a scale can be, relative to the largest
+// axis, before the matrix is treated as flattening space along it.
+const flatTolerance = 1e-12
+
+// Shear holds the three shearing factors left over once rotation and
+// scale have been taken out of a transformation. XY moves x in proportion
+// to y, XZ moves x in proportion to z, and YZ moves y in proportion to z.
+type Shear struct {
+	XY float64
+	XZ float64
+	YZ float64
+}
+
+// Decomposition is an affine transformation split into its parts.
+// Recomposing it scales first, then shears, then rotates and
+// finally translates, i.e. T * R * H * S.
+type Decomposition struct {
+	Translation tuple.Vector
+	Rotation    quaternion.Quaternion
+	Scale       tuple.Vector
+	Shear       Shear
+}
+
+// Decompose splits a 4x4 affine transformation into translation,
+// rotation, scale and shear. Reflections are returned as negative scales.
+// Projective matrices, and matrices that flatten space along
+// an axis, cannot be decomposed and return an error.
+func Decompose(m matrix.Matrix) (Decomposition, error) {
+	m4, err := m.ToMat4()
+	if err != nil {
+		return Decomposition{}, err
+	}
+	if !float.Equal(0, m4[3][0]) || !float.Equal(0, m4[3][1]) || !float.Equal(0, m4[3][2]) || !float.Equal(1, m4[3][3]) {
+		return Decomposition{}, errors.New("cannot decompose a projective matrix")
+	}
+
+	// the columns of the upper 3x3 are where the X, Y and Z axes end up
+	col := func(c int) tuple.Vector {
+		return tuple.Vec(m4[0][c], m4[1][c], m4[2][c])
+	}
+	x, y, z := col(0), col(1), col(2)
+
+	// scales are compared to the largest axis, so that uniformly tiny
+	// objects can still be decomposed
+	largest := math.Max(x.Magnitude(), math.Max(y.Magnitude(), z.Magnitude()))
+	flat := func(s float64) bool {
+		return s <= flatTolerance*largest
+	}
+
+	// Gram-Schmidt orthogonalisation, collecting scale and shear on the way
+	var d Decomposition
+	sx := x.Magnitude()
+	if flat(sx) {
+		return Decomposition{}, errors.New("cannot decompose a matrix with zero scale")
+	}
+	x = x.Divide(sx)
+
+	xy := x.Dot(y)
+	y = y.Subtract(x.Scale(xy))
+	sy := y.Magnitude()
+	if flat(sy) {
+		return Decomposition{}, errors.New("cannot decompose a matrix with zero scale")
+	}
+	y = y.Divide(sy)
+
+	xz := x.Dot(z)
+	z = z.Subtract(x.Scale(xz))
+	yz := y.Dot(z)
+	z = z.Subtract(y.Scale(yz))
+	sz := z.Magnitude()
+	if flat(sz) {
+		return Decomposition{}, errors.New("cannot decompose a matrix with zero scale")
+	}
+	z = z.Divide(sz)
+
+	// the shear is relative to the scales before any reflection is
+	// taken out, as flipping both the axes and the scales cancels out
+	d.Shear = Shear{XY: xy / sy, XZ: xz / sz, YZ: yz / sz}
+
+	// a left handed basis is a reflection, which cannot be a rotation
+	if x.Cross(y).Dot(z) < 0 {
+		sx, sy, sz = -sx, -sy, -sz
+		x, y, z = x.Negate(), y.Negate(), z.Negate()
+	}
+
+	d.Scale = tuple.Vec(sx, sy, sz)
+	d.Translation = tuple.Vec(m4[0][3], m4[1][3], m4[2][3])
+	d.Rotation, err = quaternion.FromMatrix(matrix.Matrix{
+		{x.X, y.X, z.X},
+		{x.Y, y.Y, z.Y},
+		{x.Z, y.Z, z.Z},
+	})
+	if err != nil {
+		return Decomposition{}, err
+	}
+	return d, nil
+}
+
+// Recompose builds the transformation matrix back up from its parts
+func (d Decomposition) Recompose() matrix.Matrix {
+	return matrix.Identity().
+		Scale(d.Scale.X, d.Scale.Y, d.Scale.Z).
+		Shear(d.Shear.XY, d.Shear.XZ, 0, d.Shear.YZ, 0, 0).
+		Then(d.Rotation.ToMatrix()).
+		Translate(d.Translation.X, d.Translation.Y, d.Translation.Z)
+}
+
+// Interpolate blends two decompositions, such as the transforms
+// of an object at two keyframes. Translation, scale and shear are
+// interpolated linearly, and rotation spherically.
+func Interpolate(a, b Decomposition, t float64) Decomposition {
+	lerp := func(a, b float64) float64 {
+		return a + t*(b-a)
+	}
+	return Decomposition{
+		Translation: a.Translation.Add(b.Translation.Subtract(a.Translation).Scale(t)),
+		Rotation:    quaternion.Slerp(a.Rotation, b.Rotation, t),
+		Scale:       a.Scale.Add(b.Scale.Subtract(a.Scale).Scale(t)),
+		Shear: Shear{
+			XY: lerp(a.Shear.XY, b.Shear.XY),
+			XZ: lerp(a.Shear.XZ, b.Shear.XZ),
+			YZ: lerp(a.Shear.YZ, b.Shear.YZ),
+		},
+	}
+}
+
+// String describes the decomposition for debugging,
+// with the rotation given as Euler angles in degrees.
+func (d Decomposition) String() string {
+	rx, ry, rz := d.Rotation.Euler()
+	return fmt.Sprintf(
+		"translate(%g, %g, %g) rotate(x=%g°, y=%g°, z=%g°) scale(%g, %g, %g) shear(xy=%g, xz=%g, yz=%g)",
+		round(d.Translation.X), round(d.Translation.Y), round(d.Translation.Z),
+		round(degrees(rx)), round(degrees(ry)), round(degrees(rz)),
+		round(d.Scale.X), round(d.Scale.Y), round(d.Scale.Z),
+		round(d.Shear.XY), round(d.Shear.XZ), round(d.Shear.YZ),
+	)
+}
+
+func degrees(r float64) float64 {
+	return r * 180 / math.Pi
+}
+
+// round drops the floating point noise from values printed by String
+func round(f float64) float64 {
+	r := math.Round(f*1e5) / 1e5
+	if r == 0 {
+		return 0
+	}
+	return r
+}
@@ -0,0 +1,130 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/muzfuz/raytrace/float"
+	"github.com/muzfuz/raytrace/matrix"
+	"github.com/muzfuz/raytrace/quaternion"
+	"github.com/muzfuz/raytrace/tuple"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecomposeIdentity(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := Decompose(matrix.Identity())
+	is.NoError(err)
+	is.True(d.Translation.Equal(tuple.Vec(0, 0, 0)))
+	is.True(d.Rotation.Equal(quaternion.Identity()))
+	is.True(d.Scale.Equal(tuple.Vec(1, 1, 1)))
+	is.Equal(Shear{}, d.Shear)
+}
+
+func TestDecomposeParts(t *testing.T) {
+	is := assert.New(t)
+
+	rotation := quaternion.FromEuler(0.3, -0.7, 1.1)
+	m := matrix.Identity().
+		Scale(2, 3, 4).
+		Shear(0.5, 0, 0, 0.25, 0, 0).
+		Then(rotation.ToMatrix()).
+		Translate(10, -5, 7)
+
+	d, err := Decompose(m)
+	is.NoError(err)
+	is.True(d.Translation.Equal(tuple.Vec(10, -5, 7)))
+	is.True(d.Scale.Equal(tuple.Vec(2, 3, 4)))
+	is.True(float.Equal(0.5, d.Shear.XY))
+	is.True(float.Equal(0, d.Shear.XZ))
+	is.True(float.Equal(0.25, d.Shear.YZ))
+	is.True(d.Rotation.ToMatrix().Equal(rotation.ToMatrix()))
+}
+
+func TestRecompose(t *testing.T) {
+	is := assert.New(t)
+
+	matrices := []matrix.Matrix{
+		matrix.Identity(),
+		matrix.Translation(1, 2, 3),
+		matrix.Identity().RotateX(math.Pi/2).Scale(5, 5, 5).Translate(10, 5, 7),
+		matrix.Identity().Shear(1, 0.5, 0.2, 0.3, 0.1, 0.7).RotateY(2).Translate(-1, 0, 4),
+		matrix.Scaling(-1, 2, 3),
+	}
+	for _, m := range matrices {
+		d, err := Decompose(m)
+		is.NoError(err)
+		is.True(d.Recompose().Equal(m), "%v", m)
+	}
+}
+
+func TestDecomposeReflection(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := Decompose(matrix.Scaling(-1, 1, 1))
+	is.NoError(err)
+	// the reflection is pushed into the scale, leaving a proper rotation
+	is.True(d.Scale.X < 0 && d.Scale.Y < 0 && d.Scale.Z < 0)
+	is.True(d.Recompose().Equal(matrix.Scaling(-1, 1, 1)))
+}
+
+func TestDecomposeShearedReflection(t *testing.T) {
+	is := assert.New(t)
+
+	m := matrix.Identity().Scale(-1, 2, 3).Shear(0.5, 0.2, 0, 0.3, 0, 0).Translate(1, 2, 3)
+	d, err := Decompose(m)
+	is.NoError(err)
+	is.True(d.Recompose().Equal(m))
+}
+
+func TestDecomposeErrors(t *testing.T) {
+	is := assert.New(t)
+
+	_, err := Decompose(matrix.Matrix{{1, 0}, {0, 1}})
+	is.Error(err)
+
+	projective := matrix.Identity()
+	projective[3][2] = 1
+	_, err = Decompose(projective)
+	is.Error(err)
+
+	_, err = Decompose(matrix.Scaling(1, 0, 1))
+	is.Error(err)
+	_, err = Decompose(matrix.Scaling(1, 1e-20, 1))
+	is.Error(err)
+}
+
+func TestDecomposeSmallScale(t *testing.T) {
+	is := assert.New(t)
+
+	// tiny is not the same as flat
+	d, err := Decompose(matrix.Scaling(1e-6, 2e-6, 3e-6))
+	is.NoError(err)
+	is.InEpsilon(1e-6, d.Scale.X, 1e-9)
+	is.InEpsilon(2e-6, d.Scale.Y, 1e-9)
+	is.InEpsilon(3e-6, d.Scale.Z, 1e-9)
+}
+
+func TestInterpolate(t *testing.T) {
+	is := assert.New(t)
+
+	a, _ := Decompose(matrix.Identity())
+	b, _ := Decompose(matrix.Identity().Scale(3, 3, 3).RotateZ(math.Pi/2).Translate(10, 0, 0))
+
+	is.True(Interpolate(a, b, 0).Recompose().Equal(a.Recompose()))
+	is.True(Interpolate(a, b, 1).Recompose().Equal(b.Recompose()))
+
+	mid := Interpolate(a, b, 0.5)
+	expected := matrix.Identity().Scale(2, 2, 2).RotateZ(math.Pi/4).Translate(5, 0, 0)
+	is.True(mid.Recompose().Equal(expected))
+}
+
+func TestString(t *testing.T) {
+	is := assert.New(t)
+
+	d, err := Decompose(matrix.Identity().Scale(2, 1, 1).RotateX(math.Pi/2).Translate(1, 2, 3))
+	is.NoError(err)
+	is.Equal("translate(1, 2, 3) rotate(x=90°, y=0°, z=0°) scale(2, 1, 1) shear(xy=0, xz=0, yz=0)", d.String())
+}
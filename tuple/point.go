@@ -1,10 +1,19 @@
 package tuple
 
-import "github.com/muzfuz/raytrace/float"
+import (
+	"errors"
+	"math"
+
+	"github.com/muzfuz/raytrace/float"
+)
 
 const pointW = 1.0
 
-// NewPoint returns a point Tuple
+// NewPoint returns a point Tuple.
+//
+// Deprecated: use Pt, which returns a typed Point that can only be
+// combined with points and vectors in ways that make sense. Call its
+// Tuple method where a homogeneous Tuple is still needed.
 func NewPoint(x, y, z float64) Tuple {
 	return Tuple{
 		X: x,
@@ -21,3 +30,79 @@ func (t Tuple) IsPoint() bool {
 	}
 	return false
 }
+
+// Point is a position in space.
+// Unlike a point Tuple, the compiler only allows it to be combined
+// with other points and vectors in ways that make sense: a vector can be
+// added to a point, but two points cannot be added together.
+type Point struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Pt constructs a Point. It replaces NewPoint, which returns a Tuple.
+func Pt(x, y, z float64) Point {
+	return Point{
+		X: x,
+		Y: y,
+		Z: z,
+	}
+}
+
+// Tuple converts the point to its homogeneous Tuple, with W of 1.0
+func (p Point) Tuple() Tuple {
+	return NewPoint(p.X, p.Y, p.Z)
+}
+
+// ToPoint converts a Tuple to a Point,
+// or returns an error if the tuple is not a point.
+func (t Tuple) ToPoint() (Point, error) {
+	if !t.IsPoint() {
+		return Point{}, errors.New("tuple is not a point")
+	}
+	return Pt(t.X, t.Y, t.Z), nil
+}
+
+// Equal will compare two points for equality
+func (p Point) Equal(p2 Point) bool {
+	return p.EqualWithin(p2, float.Default)
+}
+
+// EqualWithin compares two points using the given comparator
+func (p Point) EqualWithin(p2 Point, cmp float.Comparator) bool {
+	return cmp.Equal(p.X, p2.X) && cmp.Equal(p.Y, p2.Y) && cmp.Equal(p.Z, p2.Z)
+}
+
+// Add moves the point along a vector
+func (p Point) Add(v Vector) Point {
+	return Pt(p.X+v.X, p.Y+v.Y, p.Z+v.Z)
+}
+
+// Subtract returns the vector from p2 to p
+func (p Point) Subtract(p2 Point) Vector {
+	return Vec(p.X-p2.X, p.Y-p2.Y, p.Z-p2.Z)
+}
+
+// SubtractVector moves the point backwards along a vector
+func (p Point) SubtractVector(v Vector) Point {
+	return Pt(p.X-v.X, p.Y-v.Y, p.Z-v.Z)
+}
+
+// Lerp linearly interpolates between two points.
+// t of 0.0 returns p and t of 1.0 returns p2.
+func (p Point) Lerp(p2 Point, t float64) Point {
+	return p.Add(p2.Subtract(p).Scale(t))
+}
+
+// Min returns the smallest of each coordinate of the two points,
+// which is the lower corner of the box around them.
+func (p Point) Min(p2 Point) Point {
+	return Pt(math.Min(p.X, p2.X), math.Min(p.Y, p2.Y), math.Min(p.Z, p2.Z))
+}
+
+// Max returns the largest of each coordinate of the two points,
+// which is the upper corner of the box around them.
+func (p Point) Max(p2 Point) Point {
+	return Pt(math.Max(p.X, p2.X), math.Max(p.Y, p2.Y), math.Max(p.Z, p2.Z))
+}
@@ -23,3 +23,60 @@ func TestNewPoint(t *testing.T) {
 	is.True(point.IsPoint())
 	is.False(point.IsVector())
 }
+
+func TestPt(t *testing.T) {
+	is := assert.New(t)
+
+	p := Pt(4, -4, 3)
+	is.Equal(Point{X: 4, Y: -4, Z: 3}, p)
+	is.Equal(NewPoint(4, -4, 3), p.Tuple())
+	is.True(p.Tuple().IsPoint())
+}
+
+func TestToPoint(t *testing.T) {
+	is := assert.New(t)
+
+	p, err := NewPoint(1, 2, 3).ToPoint()
+	is.NoError(err)
+	is.Equal(Pt(1, 2, 3), p)
+
+	_, err = NewVector(1, 2, 3).ToPoint()
+	is.Error(err)
+}
+
+func TestPointEqual(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(Pt(1, 2, 3).Equal(Pt(1, 2, 3.000001)))
+	is.False(Pt(1, 2, 3).Equal(Pt(3, 2, 1)))
+}
+
+func TestPointArithmetic(t *testing.T) {
+	is := assert.New(t)
+
+	// adding a vector to a point gives a point
+	is.Equal(Pt(1, 1, 6), Pt(3, -2, 5).Add(Vec(-2, 3, 1)))
+
+	// subtracting two points gives the vector between them
+	is.Equal(Vec(-2, -4, -6), Pt(3, 2, 1).Subtract(Pt(5, 6, 7)))
+
+	// subtracting a vector from a point gives a point
+	is.Equal(Pt(-2, -4, -6), Pt(3, 2, 1).SubtractVector(Vec(5, 6, 7)))
+}
+
+func TestPointLerp(t *testing.T) {
+	is := assert.New(t)
+
+	a, b := Pt(0, 0, 0), Pt(2, -4, 8)
+	is.Equal(a, a.Lerp(b, 0))
+	is.Equal(b, a.Lerp(b, 1))
+	is.Equal(Pt(0.5, -1, 2), a.Lerp(b, 0.25))
+}
+
+func TestPointMinMax(t *testing.T) {
+	is := assert.New(t)
+
+	a, b := Pt(1, -2, 3), Pt(-1, 2, 3)
+	is.Equal(Pt(-1, -2, 3), a.Min(b))
+	is.Equal(Pt(1, 2, 3), a.Max(b))
+}
@@ -15,10 +15,13 @@ type Tuple struct {
 
 // Equal will compare an instance of a tuple to another instance of a tuple for equality
 func (t Tuple) Equal(t2 Tuple) bool {
-	if float.Equal(t.X, t2.X) && float.Equal(t.Y, t2.Y) && float.Equal(t.Z, t2.Z) && float.Equal(t.W, t2.W) {
-		return true
-	}
-	return false
+	return t.EqualWithin(t2, float.Default)
+}
+
+// EqualWithin compares two tuples using the given comparator
+// instead of the package default
+func (t Tuple) EqualWithin(t2 Tuple, cmp float.Comparator) bool {
+	return cmp.Equal(t.X, t2.X) && cmp.Equal(t.Y, t2.Y) && cmp.Equal(t.Z, t2.Z) && cmp.Equal(t.W, t2.W)
 }
 
 // Add will add the values of two tuples together
@@ -3,6 +3,8 @@ package tuple
 import (
 	"testing"
 
+	"github.com/muzfuz/raytrace/float"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,6 +26,22 @@ func TestEqualsWhenFalse(t *testing.T) {
 	is.False(tup.Equal(anotherTup))
 }
 
+func TestEqualWithin(t *testing.T) {
+	is := assert.New(t)
+
+	// far from the origin the default absolute tolerance is too strict
+	tup := NewPoint(1e9, 2, 3)
+	anotherTup := NewPoint(1e9+0.01, 2, 3)
+
+	is.False(tup.Equal(anotherTup))
+	is.True(tup.EqualWithin(anotherTup, float.Tolerance{Abs: 0.00001, Rel: 1e-9}))
+	is.False(tup.EqualWithin(NewVector(1e9, 2, 3), float.Tolerance{Abs: 0.00001, Rel: 1e-9}))
+
+	is.True(Pt(1e9, 2, 3).EqualWithin(Pt(1e9+0.01, 2, 3), float.Tolerance{Rel: 1e-9}))
+	is.True(Vec(1e9, 2, 3).EqualWithin(Vec(1e9+0.01, 2, 3), float.Tolerance{Rel: 1e-9}))
+	is.False(Vec(1, 2, 3).EqualWithin(Vec(1.000001, 2, 3), float.ULPs(4)))
+}
+
 func TestAddVecToPoint(t *testing.T) {
 	is := assert.New(t)
 	expected := NewPoint(1, 1, 6)
@@ -9,7 +9,11 @@ import (
 
 const vectorW = 0.0
 
-// NewVector returns a vector Tuple
+// NewVector returns a vector Tuple.
+//
+// Deprecated: use Vec, which returns a typed Vector whose dot and
+// cross products cannot fail. Call its Tuple method where a
+// homogeneous Tuple is still needed.
 func NewVector(x, y, z float64) Tuple {
 	return Tuple{
 		X: x,
@@ -74,3 +78,152 @@ func CrossProduct(a, b Tuple) (Tuple, error) {
 		(a.X*b.Y)-(a.Y*b.X),
 	), nil
 }
+
+// Vector is a direction and a magnitude.
+// Unlike a vector Tuple, its methods only accept other vectors,
+// so the dot and cross products cannot fail.
+type Vector struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Vec constructs a Vector. It replaces NewVector, which returns a Tuple.
+func Vec(x, y, z float64) Vector {
+	return Vector{
+		X: x,
+		Y: y,
+		Z: z,
+	}
+}
+
+// Tuple converts the vector to its homogeneous Tuple, with W of 0.0
+func (v Vector) Tuple() Tuple {
+	return NewVector(v.X, v.Y, v.Z)
+}
+
+// ToVector converts a Tuple to a Vector,
+// or returns an error if the tuple is not a vector.
+func (t Tuple) ToVector() (Vector, error) {
+	if !t.IsVector() {
+		return Vector{}, errors.New("tuple is not a vector")
+	}
+	return Vec(t.X, t.Y, t.Z), nil
+}
+
+// Equal will compare two vectors for equality
+func (v Vector) Equal(v2 Vector) bool {
+	return v.EqualWithin(v2, float.Default)
+}
+
+// EqualWithin compares two vectors using the given comparator
+func (v Vector) EqualWithin(v2 Vector, cmp float.Comparator) bool {
+	return cmp.Equal(v.X, v2.X) && cmp.Equal(v.Y, v2.Y) && cmp.Equal(v.Z, v2.Z)
+}
+
+// Add will add two vectors together
+func (v Vector) Add(v2 Vector) Vector {
+	return Vec(v.X+v2.X, v.Y+v2.Y, v.Z+v2.Z)
+}
+
+// Subtract will subtract v2 from v
+func (v Vector) Subtract(v2 Vector) Vector {
+	return Vec(v.X-v2.X, v.Y-v2.Y, v.Z-v2.Z)
+}
+
+// Negate returns the vector pointing the opposite way
+func (v Vector) Negate() Vector {
+	return Vec(-v.X, -v.Y, -v.Z)
+}
+
+// Scale will scale the vector up or down
+func (v Vector) Scale(scalar float64) Vector {
+	return Vec(v.X*scalar, v.Y*scalar, v.Z*scalar)
+}
+
+// Divide allows us to scale down the vector via division
+func (v Vector) Divide(divisor float64) Vector {
+	return Vec(v.X/divisor, v.Y/divisor, v.Z/divisor)
+}
+
+// Magnitude returns the length of the vector
+func (v Vector) Magnitude() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+// Normalize returns the unit vector pointing the same way
+func (v Vector) Normalize() Vector {
+	return v.Divide(v.Magnitude())
+}
+
+// Dot returns the dot product of two vectors
+func (v Vector) Dot(v2 Vector) float64 {
+	return v.X*v2.X + v.Y*v2.Y + v.Z*v2.Z
+}
+
+// Cross returns the vector perpendicular to both vectors
+func (v Vector) Cross(v2 Vector) Vector {
+	return Vec(
+		v.Y*v2.Z-v.Z*v2.Y,
+		v.Z*v2.X-v.X*v2.Z,
+		v.X*v2.Y-v.Y*v2.X,
+	)
+}
+
+// Reflect returns the vector reflected around the normal
+func (v Vector) Reflect(normal Vector) Vector {
+	return v.Subtract(normal.Scale(2 * v.Dot(normal)))
+}
+
+// Lerp linearly interpolates between two vectors.
+// t of 0.0 returns v and t of 1.0 returns v2.
+func (v Vector) Lerp(v2 Vector, t float64) Vector {
+	return v.Add(v2.Subtract(v).Scale(t))
+}
+
+// Min returns the smallest of each component of the two vectors
+func (v Vector) Min(v2 Vector) Vector {
+	return Vec(math.Min(v.X, v2.X), math.Min(v.Y, v2.Y), math.Min(v.Z, v2.Z))
+}
+
+// Max returns the largest of each component of the two vectors
+func (v Vector) Max(v2 Vector) Vector {
+	return Vec(math.Max(v.X, v2.X), math.Max(v.Y, v2.Y), math.Max(v.Z, v2.Z))
+}
+
+// Abs returns the vector with every component made positive
+func (v Vector) Abs() Vector {
+	return Vec(math.Abs(v.X), math.Abs(v.Y), math.Abs(v.Z))
+}
+
+// Angle returns the angle between two vectors, in radians between 0 and π.
+// It uses atan2 rather than acos of the dot product,
+// so it stays accurate for nearly parallel vectors.
+func (v Vector) Angle(v2 Vector) float64 {
+	return math.Atan2(v.Cross(v2).Magnitude(), v.Dot(v2))
+}
+
+// Project returns the part of v that points along onto.
+// onto does not need to be normalized, but must not be the zero vector.
+func (v Vector) Project(onto Vector) Vector {
+	return onto.Scale(v.Dot(onto) / onto.Dot(onto))
+}
+
+// Reject returns the part of v perpendicular to onto,
+// so that v.Project(onto).Add(v.Reject(onto)) is v again.
+func (v Vector) Reject(onto Vector) Vector {
+	return v.Subtract(v.Project(onto))
+}
+
+// OrthonormalBasis returns two unit vectors that are perpendicular
+// to each other and to the unit vector n, such that u x v = n.
+// It uses the branchless method from Duff et al., "Building an
+// Orthonormal Basis, Revisited", which has no singularities.
+func OrthonormalBasis(n Vector) (Vector, Vector) {
+	sign := math.Copysign(1, n.Z)
+	a := -1 / (sign + n.Z)
+	b := n.X * n.Y * a
+	u := Vec(1+sign*n.X*n.X*a, sign*b, -sign*n.X)
+	v := Vec(b, sign+n.Y*n.Y*a, -n.Y)
+	return u, v
+}
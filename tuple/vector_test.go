@@ -4,6 +4,8 @@ import (
 	"math"
 	"testing"
 
+	"github.com/muzfuz/raytrace/float"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -81,3 +83,145 @@ func TestCrossProduct(t *testing.T) {
 	is.NoError(err)
 	is.Equal(NewVector(1, -2, 1), cross2)
 }
+
+func TestVec(t *testing.T) {
+	is := assert.New(t)
+
+	v := Vec(4, -4, 3)
+	is.Equal(Vector{X: 4, Y: -4, Z: 3}, v)
+	is.Equal(NewVector(4, -4, 3), v.Tuple())
+	is.True(v.Tuple().IsVector())
+}
+
+func TestToVector(t *testing.T) {
+	is := assert.New(t)
+
+	v, err := NewVector(1, 2, 3).ToVector()
+	is.NoError(err)
+	is.Equal(Vec(1, 2, 3), v)
+
+	_, err = NewPoint(1, 2, 3).ToVector()
+	is.Error(err)
+}
+
+func TestVectorArithmetic(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(Vec(1, 2, 3).Equal(Vec(1, 2, 3.000001)))
+	is.Equal(Vec(1, 1, 6), Vec(3, -2, 5).Add(Vec(-2, 3, 1)))
+	is.Equal(Vec(-2, -4, -6), Vec(3, 2, 1).Subtract(Vec(5, 6, 7)))
+	is.Equal(Vec(-1, 2, -3), Vec(1, -2, 3).Negate())
+	is.Equal(Vec(3.5, -7, 10.5), Vec(1, -2, 3).Scale(3.5))
+	is.Equal(Vec(0.5, -1, 1.5), Vec(1, -2, 3).Divide(2))
+}
+
+func TestVecMagnitudeAndNormalize(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(1.0, Vec(0, 1, 0).Magnitude())
+	is.Equal(math.Sqrt(14), Vec(-1, -2, -3).Magnitude())
+
+	is.Equal(Vec(1, 0, 0), Vec(4, 0, 0).Normalize())
+	n := Vec(1, 2, 3).Normalize()
+	is.True(n.Equal(Vec(0.26726, 0.53452, 0.80178)))
+	is.True(float.Equal(1.0, n.Magnitude()))
+}
+
+func TestVecDotAndCross(t *testing.T) {
+	is := assert.New(t)
+
+	a := Vec(1, 2, 3)
+	b := Vec(2, 3, 4)
+
+	is.Equal(20.0, a.Dot(b))
+	is.Equal(Vec(-1, 2, -1), a.Cross(b))
+	is.Equal(Vec(1, -2, 1), b.Cross(a))
+}
+
+func TestVecReflect(t *testing.T) {
+	is := assert.New(t)
+
+	is.True(Vec(1, -1, 0).Reflect(Vec(0, 1, 0)).Equal(Vec(1, 1, 0)))
+	is.True(Vec(0, -1, 0).Reflect(Vec(math.Sqrt(2)/2, math.Sqrt(2)/2, 0)).Equal(Vec(1, 0, 0)))
+}
+
+func TestVectorLerp(t *testing.T) {
+	is := assert.New(t)
+
+	a, b := Vec(1, 0, 0), Vec(0, 1, 0)
+	is.Equal(a, a.Lerp(b, 0))
+	is.Equal(b, a.Lerp(b, 1))
+	is.Equal(Vec(0.5, 0.5, 0), a.Lerp(b, 0.5))
+	is.Equal(Vec(2, -1, 0), a.Lerp(b, -1))
+}
+
+func TestVectorMinMaxAbs(t *testing.T) {
+	is := assert.New(t)
+
+	a, b := Vec(1, -2, 3), Vec(-1, 2, 0)
+	is.Equal(Vec(-1, -2, 0), a.Min(b))
+	is.Equal(Vec(1, 2, 3), a.Max(b))
+	is.Equal(Vec(1, 2, 3), a.Abs())
+	is.Equal(Vec(1, 2, 0), b.Abs())
+}
+
+func TestVectorAngle(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		a     Vector
+		b     Vector
+		angle float64
+	}{
+		{Vec(1, 0, 0), Vec(1, 0, 0), 0},
+		{Vec(1, 0, 0), Vec(0, 3, 0), math.Pi / 2},
+		{Vec(1, 0, 0), Vec(-2, 0, 0), math.Pi},
+		{Vec(1, 0, 0), Vec(1, 1, 0), math.Pi / 4},
+		{Vec(0, 0, 1), Vec(1, 1, 1), math.Acos(1 / math.Sqrt(3))},
+	}
+	for _, tt := range tests {
+		is.True(float.Equal(tt.angle, tt.a.Angle(tt.b)))
+		is.True(float.Equal(tt.angle, tt.b.Angle(tt.a)))
+	}
+
+	// stays accurate where acos of the dot product would round to 0
+	is.InDelta(1e-9, Vec(1, 0, 0).Angle(Vec(1, 1e-9, 0)), 1e-18)
+}
+
+func TestVectorProjectReject(t *testing.T) {
+	is := assert.New(t)
+
+	v := Vec(3, 4, 5)
+	onto := Vec(0, 2, 0)
+	is.Equal(Vec(0, 4, 0), v.Project(onto))
+	is.Equal(Vec(3, 0, 5), v.Reject(onto))
+
+	// the two parts add back up to the original vector
+	onto = Vec(1, -2, 0.5)
+	p, r := v.Project(onto), v.Reject(onto)
+	is.True(p.Add(r).Equal(v))
+	is.True(float.Equal(0, r.Dot(onto)))
+	is.True(float.Equal(0, p.Cross(onto).Magnitude()))
+}
+
+func TestOrthonormalBasis(t *testing.T) {
+	is := assert.New(t)
+
+	normals := []Vector{
+		Vec(0, 0, 1),
+		Vec(0, 0, -1),
+		Vec(1, 0, 0),
+		Vec(0, -1, 0),
+		Vec(1, 2, 3).Normalize(),
+		Vec(-1, 1, -0.0001).Normalize(),
+	}
+	for _, n := range normals {
+		u, v := OrthonormalBasis(n)
+		is.True(float.Equal(1, u.Magnitude()))
+		is.True(float.Equal(1, v.Magnitude()))
+		is.True(float.Equal(0, u.Dot(v)))
+		is.True(float.Equal(0, u.Dot(n)))
+		is.True(float.Equal(0, v.Dot(n)))
+		is.True(u.Cross(v).Equal(n))
+	}
+}